@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// bundleMain implements the "bundle" subcommand, which writes a self-contained copy of a JSON
+// Schema with all referenced schemas inlined (see jsonschema.Bundle).
+func bundleMain(args []string) {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	outputFile := flags.String("o", "", "write result to file instead of stdout")
+	fetchHTTP := flags.Bool("http", false, "fetch schemas referred to by HTTP(S) URIs in $ref values")
+	httpServer := flags.String("http-server", "", "fetch HTTP(S) schemas from this server (such as a local mirror) instead of their own hosts")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage of go-jsonschema-compiler bundle:")
+		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler bundle [flags] file")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: bundle requires exactly 1 JSON Schema file.")
+		fmt.Fprintln(os.Stderr)
+		flags.Usage()
+		os.Exit(2)
+	}
+	filename := flags.Arg(0)
+
+	loader, err := newLoader(*fetchHTTP, *httpServer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: %s.\n", err)
+		os.Exit(2)
+	}
+	schema, err := readSchema(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error reading JSON Schema from %s: %s.\n", filename, err)
+		os.Exit(2)
+	}
+	uri, err := fileURI(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error reading JSON Schema from %s: %s.\n", filename, err)
+		os.Exit(2)
+	}
+
+	bundle, err := jsonschema.Bundle(schema, uri, loader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: bundle error: %s.\n", err)
+		os.Exit(2)
+	}
+	out, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: output error: %s.\n", err)
+		os.Exit(2)
+	}
+	out = append(out, '\n')
+
+	if *outputFile == "" {
+		os.Stdout.Write(out)
+	} else if err := ioutil.WriteFile(*outputFile, out, 0666); err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: output error: %s.\n", err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// checkMain implements the "check" subcommand, which checks that JSON Schema files are valid
+// against the draft-07 meta-schema (see jsonschema.CheckSchema) and prints each problem with its
+// location.
+func checkMain(args []string) {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage of go-jsonschema-compiler check:")
+		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler check files...")
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: no JSON Schema files listed.")
+		fmt.Fprintln(os.Stderr)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var invalid bool
+	for _, filename := range flags.Args() {
+		var data []byte
+		var err error
+		if filename == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(filename)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error reading JSON Schema from %s: %s.\n", filename, err)
+			os.Exit(2)
+		}
+
+		err = jsonschema.CheckSchema(data)
+		if verr, ok := err.(*jsonschema.ValidationError); ok {
+			invalid = true
+			for _, cause := range verr.Causes {
+				pos := cause.InstancePosition
+				pos.Filename = filename
+				fmt.Printf("%s: %s\n", pos, cause)
+			}
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error checking JSON Schema %s: %s.\n", filename, err)
+			os.Exit(2)
+		}
+	}
+	if invalid {
+		os.Exit(1)
+	}
+}
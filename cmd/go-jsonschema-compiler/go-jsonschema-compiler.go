@@ -4,22 +4,33 @@ package main
 
 import (
 	"bytes"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"go/ast"
 	"go/format"
 	"go/token"
 	"io"
+	"io/ioutil"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/sourcegraph/go-jsonschema/compiler"
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
 var (
-	packageName = flag.String("pkg", "schema", "Go package name to use in emitted source code")
-	outputFile  = flag.String("o", "", "write result to file instead of stdout")
+	packageName  = flag.String("pkg", "schema", "Go package name to use in emitted source code")
+	outputFile   = flag.String("o", "", "write result to file instead of stdout")
+	fetchHTTP    = flag.Bool("http", false, "fetch schemas referred to by HTTP(S) URIs in $ref values")
+	httpServer   = flag.String("http-server", "", "fetch HTTP(S) schemas from this server (such as a local mirror) instead of their own hosts")
+	docOrder     = flag.Bool("document-order", false, "emit struct fields in the order of the properties in the schema (instead of sorted by name)")
+	omitReadOnly = flag.Bool("omit-read-only", false, "omit properties with \"readOnly\": true from struct types (such as for request types)")
+	validate     = flag.Bool("validate", false, "generate a Validate method on each struct type that checks the schema constraints")
+	enumTypes    = flag.Bool("enum-types", false, "declare a named type with constants for each string or integer enum")
+	strictEnums  = flag.Bool("strict-enums", false, "generate an UnmarshalJSON method on each enum type that rejects unknown values (requires -enum-types)")
+	formatTypes  = flag.String("format-types", "", "comma-separated list of formats (such as date-time,uuid) whose strings are held by richer Go types (such as time.Time), or \"all\" (all but duration)")
 )
 
 func main() {
@@ -27,27 +38,64 @@ func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
 		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler [flags] files...")
+		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler bundle [flags] file")
+		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler check files...")
+		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler lint [flags] files...")
 		fmt.Fprintln(os.Stderr, "Flags:")
 		flag.PrintDefaults()
 	}
+	if flag.NArg() > 0 && flag.Arg(0) == "bundle" {
+		bundleMain(flag.Args()[1:])
+		return
+	}
+	if flag.NArg() > 0 && flag.Arg(0) == "check" {
+		checkMain(flag.Args()[1:])
+		return
+	}
+	if flag.NArg() > 0 && flag.Arg(0) == "lint" {
+		lintMain(flag.Args()[1:])
+		return
+	}
 	if flag.NArg() == 0 {
 		fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: no JSON Schema files listed.")
 		fmt.Fprintln(os.Stderr)
 		flag.Usage()
 		os.Exit(2)
 	}
+	if *strictEnums && !*enumTypes {
+		fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: -strict-enums requires -enum-types.")
+		fmt.Fprintln(os.Stderr)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	loader, err := newLoader(*fetchHTTP, *httpServer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: %s.\n", err)
+		os.Exit(2)
+	}
+	opts := compiler.Options{Loader: loader, BaseURIs: map[*jsonschema.Schema]*url.URL{}, DocumentOrder: *docOrder, OmitReadOnly: *omitReadOnly, ValidateMethods: *validate, EnumTypes: *enumTypes, StrictEnums: *strictEnums}
+	opts.FormatTypes, err = parseFormatTypes(*formatTypes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: %s.\n", err)
+		os.Exit(2)
+	}
 
 	schemas := make([]*jsonschema.Schema, flag.NArg())
 	for i, filename := range flag.Args() {
-		var err error
 		schemas[i], err = readSchema(filename)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error reading JSON Schema from %s: %s.\n", filename, err)
 			os.Exit(2)
 		}
+		opts.BaseURIs[schemas[i]], err = fileURI(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error reading JSON Schema from %s: %s.\n", filename, err)
+			os.Exit(2)
+		}
 	}
 
-	decls, imports, err := compiler.Compile(schemas)
+	decls, imports, err := compiler.CompileWithOptions(schemas, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: compilation error: %s.\n", err)
 		os.Exit(2)
@@ -62,7 +110,12 @@ func main() {
 		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: code formatting error: %s.\n", err)
 		os.Exit(2)
 	}
-	out := buf.Bytes()
+	// Format the source again to align the comments, which have no positions in the generated AST.
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: code formatting error: %s.\n", err)
+		os.Exit(2)
+	}
 	if !bytes.HasSuffix(out, []byte("\n")) {
 		out = append(out, '\n')
 	}
@@ -98,9 +151,68 @@ func readSchema(filename string) (*jsonschema.Schema, error) {
 	}
 	defer f.Close()
 
-	var schema *jsonschema.Schema
-	if err := json.NewDecoder(f).Decode(&schema); err != nil {
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.ParseSchema(data, filename)
+}
+
+// newLoader returns the loader for schemas referred to by $ref values. Schemas are loaded from
+// files (relative to the referring file) and, if fetchHTTP is true or httpServer is set, over
+// HTTP(S).
+func newLoader(fetchHTTP bool, httpServer string) (jsonschema.Loader, error) {
+	loader := jsonschema.SchemeLoader{"file": jsonschema.FileLoader{}}
+	if fetchHTTP || httpServer != "" {
+		httpLoader := jsonschema.HTTPLoader{}
+		if httpServer != "" {
+			var err error
+			httpLoader.Server, err = url.Parse(httpServer)
+			if err != nil {
+				return nil, fmt.Errorf("invalid -http-server URL: %s", err)
+			}
+		}
+		loader["http"] = httpLoader
+		loader["https"] = httpLoader
+	}
+	return loader, nil
+}
+
+// parseFormatTypes returns the Go types (from compiler.DefaultFormatTypes, or
+// compiler.DurationFormatType for "duration") for the formats in the comma-separated list (or for
+// all formats in compiler.DefaultFormatTypes, if the list is "all").
+func parseFormatTypes(list string) (map[string]compiler.FormatType, error) {
+	if list == "" {
+		return nil, nil
+	}
+	formatTypes := map[string]compiler.FormatType{}
+	for _, format := range strings.Split(list, ",") {
+		if format == "all" {
+			for format, typ := range compiler.DefaultFormatTypes {
+				formatTypes[format] = typ
+			}
+			continue
+		}
+		typ, ok := compiler.DefaultFormatTypes[format]
+		if format == "duration" {
+			typ, ok = compiler.DurationFormatType, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("no Go type for format %q in -format-types", format)
+		}
+		formatTypes[format] = typ
+	}
+	return formatTypes, nil
+}
+
+// fileURI returns the file URI for the named file, or nil for "-" (stdin).
+func fileURI(filename string) (*url.URL, error) {
+	if filename == "-" {
+		return nil, nil
+	}
+	path, err := filepath.Abs(filename)
+	if err != nil {
 		return nil, err
 	}
-	return schema, nil
+	return &url.URL{Scheme: "file", Path: filepath.ToSlash(path)}, nil
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/lint"
+)
+
+// lintMain implements the "lint" subcommand, which checks JSON Schema files for common mistakes
+// (see package lint) and prints each problem with its location.
+func lintMain(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	disable := flags.String("disable", "", "comma-separated names of lint rules to disable")
+	listRules := flags.Bool("rules", false, "list the lint rules and exit")
+	fetchHTTP := flags.Bool("http", false, "fetch schemas referred to by HTTP(S) URIs in $ref values")
+	httpServer := flags.String("http-server", "", "fetch HTTP(S) schemas from this server (such as a local mirror) instead of their own hosts")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage of go-jsonschema-compiler lint:")
+		fmt.Fprintln(os.Stderr, "\tgo-jsonschema-compiler lint [flags] files...")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *listRules {
+		for _, rule := range lint.Rules {
+			fmt.Printf("%s\t%s\n", rule.Name, rule.Doc)
+		}
+		return
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "go-jsonschema-compiler: no JSON Schema files listed.")
+		fmt.Fprintln(os.Stderr)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	loader, err := newLoader(*fetchHTTP, *httpServer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: %s.\n", err)
+		os.Exit(2)
+	}
+	opts := lint.Options{Loader: loader, Disabled: map[string]bool{}}
+	if *disable != "" {
+		for _, name := range strings.Split(*disable, ",") {
+			opts.Disabled[strings.TrimSpace(name)] = true
+		}
+	}
+
+	var found bool
+	for _, filename := range flags.Args() {
+		schema, err := readSchema(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error reading JSON Schema from %s: %s.\n", filename, err)
+			os.Exit(2)
+		}
+		if opts.URI, err = fileURI(filename); err != nil {
+			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: error reading JSON Schema from %s: %s.\n", filename, err)
+			os.Exit(2)
+		}
+		problems, err := lint.Lint(schema, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go-jsonschema-compiler: lint error: %s.\n", err)
+			os.Exit(2)
+		}
+		for _, problem := range problems {
+			found = true
+			fmt.Println(problem)
+		}
+	}
+	if found {
+		os.Exit(1)
+	}
+}
@@ -4,34 +4,92 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"net/url"
 	"sort"
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
+// Options configures the compiler.
+type Options struct {
+	// Loader loads JSON Schema documents that are referred to by "$ref" but are not among the
+	// schemas being compiled. Each document is loaded at most once, and Go types are also generated
+	// for the loaded documents. If nil, all referenced documents must be among the schemas being
+	// compiled.
+	Loader jsonschema.Loader
+
+	// BaseURIs maps schemas being compiled to the URIs that they were retrieved from (such as
+	// file:///path/to/schema.json). A schema's URI is used to resolve relative "$ref" values in the
+	// schema (such as "other.json"), and other schemas can refer to the schema by its URI.
+	BaseURIs map[*jsonschema.Schema]*url.URL
+
+	// DocumentOrder causes the fields of generated struct types to be in the order in which the
+	// properties appear in the schema document (see jsonschema.Schema.KeyOrder), instead of sorted
+	// by name.
+	DocumentOrder bool
+
+	// OmitReadOnly causes properties whose schemas have "readOnly": true to be omitted from the
+	// generated struct types. This is useful for generating the types of request bodies (which the
+	// client sends), when the schemas describe the server's responses.
+	OmitReadOnly bool
+
+	// ValidateMethods causes a Validate method to be generated on each struct type. The method
+	// checks the values of the fields against the constraints in their schemas (such as
+	// "minLength", "maximum", "pattern", "enum", "minItems" and "uniqueItems") in plain Go code,
+	// without interpreting the schema at run time. It returns an error for the first unsatisfied
+	// constraint, prefixed with the JSON Pointer of the invalid value (such as "#/items/0/name:
+	// string is shorter than the minimum length 1"), or nil if the value is valid.
+	//
+	// Because an optional property whose Go type can't represent absence (such as a string field
+	// with omitempty) is indistinguishable from its zero value, such fields are only checked if
+	// they are not the zero value. Required properties are not checked for presence.
+	ValidateMethods bool
+
+	// EnumTypes causes a named Go type to be declared for each string or integer schema with an
+	// "enum" of 2 or more values (such as `type GitURLType string`), instead of using the Go
+	// builtin type. An exported constant is declared for each enum value, and the type has an
+	// IsValid method that reports whether a value is one of the enum values.
+	EnumTypes bool
+
+	// StrictEnums causes the enum types (see EnumTypes) to have an UnmarshalJSON method that
+	// returns an error if the JSON value is not one of the enum values.
+	StrictEnums bool
+
+	// FormatTypes maps values of "format" to the Go types that are used for string schemas with
+	// the format (such as time.Time for "date-time"), instead of string. DefaultFormatTypes has Go
+	// types for some common formats.
+	FormatTypes map[string]FormatType
+}
+
 // Compile generates Go declarations for types that hold values described by the JSON Schemas.
 //
 // 1. Parse (per-schema)
 // 2. Resolve references (all schemas)
 // 3. Generate code (per-schema)
 func Compile(schemas []*jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error) {
+	return CompileWithOptions(schemas, Options{})
+}
+
+// CompileWithOptions is like Compile, except that it uses the given options.
+func CompileWithOptions(schemas []*jsonschema.Schema, opts Options) ([]ast.Decl, []*ast.ImportSpec, error) {
 	//
 	// Step 1: Parse (per-schema)
 	//
 	locationsByRoot := make(schemaLocationsByRoot, len(schemas))
 	for _, root := range schemas {
 		var err error
-		locationsByRoot[root], err = parseSchema(root)
+		locationsByRoot[root], err = parseSchema(root, opts.EnumTypes)
 		if err != nil {
 			return nil, nil, err
 		}
 	}
 
 	//
-	// Step 2: Resolve references (all schemas together)
+	// Step 2: Resolve references (all schemas together). This also parses documents that are
+	// loaded to resolve references.
 	//
-	resolutions, err := resolveReferences(locationsByRoot)
+	resolutions, err := newResolver(opts).resolveReferences(locationsByRoot)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -41,20 +99,36 @@ func Compile(schemas []*jsonschema.Schema) ([]ast.Decl, []*ast.ImportSpec, error
 	//
 	var allDecls []ast.Decl
 	var allImports []*ast.ImportSpec
+	enumDecls := map[string]*enumDecl{}
 	for _, schemas := range locationsByRoot {
-		decls, imports, err := generateDecls(schemas, resolutions, locationsByRoot)
+		decls, imports, err := generateDecls(schemas, resolutions, locationsByRoot, enumDecls, opts)
 		if err != nil {
 			return nil, nil, errors.WithMessage(err, "generating decls")
 		}
 		allDecls = append(allDecls, decls...)
 		allImports = append(allImports, imports...)
 	}
+	for _, enum := range enumDecls {
+		allDecls = append(allDecls, enum.decls...)
+		allImports = append(allImports, enum.imports...)
+	}
 	// Sort decls.
 	sort.SliceStable(allDecls, func(i, j int) bool {
 		name := func(k int) string {
 			switch d := allDecls[k].(type) {
 			case *ast.GenDecl:
-				return d.Specs[0].(*ast.TypeSpec).Name.Name
+				switch s := d.Specs[0].(type) {
+				case *ast.TypeSpec:
+					return s.Name.Name
+				case *ast.ValueSpec:
+					// Keep the constants of an enum type with the type.
+					if typ, ok := s.Type.(*ast.Ident); ok {
+						return typ.Name
+					}
+					return s.Names[0].Name
+				default:
+					panic(fmt.Sprintf("unhandled %T", s))
+				}
 			case *ast.FuncDecl:
 				return derefPtrType(d.Recv.List[0].Type).Name
 			default:
@@ -8,9 +8,11 @@ import (
 	"go/format"
 	"go/token"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"testing"
 
@@ -34,6 +36,19 @@ func TestCompiler(t *testing.T) {
 	}
 }
 
+// testCaseOptions sets the options for the test cases (in testdata) that don't use the default
+// options.
+var testCaseOptions = map[string]func(*Options){
+	"enum-types": func(opts *Options) { opts.EnumTypes, opts.StrictEnums = true, true },
+	"format-types": func(opts *Options) {
+		opts.FormatTypes = map[string]FormatType{"duration": DurationFormatType}
+		for format, typ := range DefaultFormatTypes {
+			opts.FormatTypes[format] = typ
+		}
+	},
+	"validate": func(opts *Options) { opts.ValidateMethods = true },
+}
+
 func testCompiler(t *testing.T, dir string) {
 	entries, err := ioutil.ReadDir(dir)
 	if err != nil {
@@ -41,6 +56,10 @@ func testCompiler(t *testing.T, dir string) {
 	}
 
 	var schemas []*jsonschema.Schema
+	opts := Options{Loader: jsonschema.FileLoader{}, BaseURIs: map[*jsonschema.Schema]*url.URL{}}
+	if setOptions, ok := testCaseOptions[filepath.Base(dir)]; ok {
+		setOptions(&opts)
+	}
 	goFiles := map[string][]byte{}
 	for _, entry := range entries {
 		if entry.Mode().IsDir() {
@@ -57,12 +76,17 @@ func testCompiler(t *testing.T, dir string) {
 				t.Fatalf("unmarshal %s: %s", entry.Name(), err)
 			}
 			schemas = append(schemas, &schema)
+			uri, err := fileURI(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts.BaseURIs[&schema] = uri
 		case ".go":
 			goFiles[entry.Name()] = data
 		}
 	}
 
-	decls, imports, err := Compile(schemas)
+	decls, imports, err := CompileWithOptions(schemas, opts)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -71,7 +95,10 @@ func testCompiler(t *testing.T, dir string) {
 	if err := format.Node(&buf, token.NewFileSet(), file); err != nil {
 		t.Fatal(err)
 	}
-	out := buf.Bytes()
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
 	if !bytes.HasSuffix(out, []byte("\n")) {
 		out = append(out, '\n')
 	}
@@ -91,6 +118,14 @@ func testCompiler(t *testing.T, dir string) {
 	}
 }
 
+func fileURI(path string) (*url.URL, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return &url.URL{Scheme: "file", Path: filepath.ToSlash(path)}, nil
+}
+
 func diff(path string, data []byte) (string, error) {
 	cmd := exec.Command("diff", "-N", "-u", path, "-")
 	cmd.Stdin = bytes.NewReader(data)
@@ -104,3 +139,128 @@ func diff(path string, data []byte) (string, error) {
 	}
 	return string(data), err
 }
+
+func TestCompile_errorPosition(t *testing.T) {
+	data := "{\n  \"title\": \"a\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"b\": {\"$ref\": \"#/definitions/missing\"}\n  }\n}"
+	schema, err := jsonschema.ParseSchema([]byte(data), "a.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = Compile([]*jsonschema.Schema{schema})
+	if want := "a.json:5:19: "; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("got error %v, want prefix %q", err, want)
+	}
+}
+
+func TestCompile_documentOrder(t *testing.T) {
+	var schema *jsonschema.Schema
+	data := `{"title": "a", "type": "object", "properties": {"id": {"type": "string"}, "name": {"type": "string"}, "age": {"type": "integer"}}}`
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatal(err)
+	}
+	for documentOrder, want := range map[bool][]string{
+		false: {"Age", "Id", "Name"},
+		true:  {"Id", "Name", "Age"},
+	} {
+		decls, _, err := CompileWithOptions([]*jsonschema.Schema{schema}, Options{DocumentOrder: documentOrder})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields []string
+		for _, f := range decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List {
+			fields = append(fields, f.Names[0].Name)
+		}
+		if strings.Join(fields, " ") != strings.Join(want, " ") {
+			t.Errorf("DocumentOrder %v: got fields %q, want %q", documentOrder, fields, want)
+		}
+	}
+}
+
+func TestCompile_taggedUnionNullConst(t *testing.T) {
+	var schema *jsonschema.Schema
+	data := `{"title": "u", "type": "object", "!go": {"taggedUnionType": true}, "oneOf": [
+  {"title": "a", "type": "object", "required": ["kind"], "properties": {"kind": {"type": "string", "const": null}}},
+  {"title": "b", "type": "object", "required": ["kind"], "properties": {"kind": {"type": "string", "const": "b"}}}
+]}`
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := Compile([]*jsonschema.Schema{schema})
+	if want := `discriminant property "kind" must not be null`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v, want it to contain %q", err, want)
+	}
+}
+
+func TestCompile_invalidMultipleOf(t *testing.T) {
+	for _, multipleOf := range []string{"0", "-2"} {
+		var schema *jsonschema.Schema
+		data := `{"title": "a", "type": "object", "properties": {"n": {"type": "integer", "multipleOf": ` + multipleOf + `}}}`
+		if err := json.Unmarshal([]byte(data), &schema); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := CompileWithOptions([]*jsonschema.Schema{schema}, Options{ValidateMethods: true})
+		if want := "multipleOf must be greater than 0"; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("multipleOf %s: got error %v, want it to contain %q", multipleOf, err, want)
+		}
+	}
+}
+
+func TestCompile_omitReadOnly(t *testing.T) {
+	var schema *jsonschema.Schema
+	data := `{"title": "a", "type": "object", "properties": {"id": {"type": "string", "readOnly": true}, "name": {"type": "string"}}}`
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatal(err)
+	}
+	for omitReadOnly, want := range map[bool][]string{
+		false: {"Id", "Name"},
+		true:  {"Name"},
+	} {
+		decls, _, err := CompileWithOptions([]*jsonschema.Schema{schema}, Options{OmitReadOnly: omitReadOnly})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields []string
+		for _, f := range decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List {
+			fields = append(fields, f.Names[0].Name)
+		}
+		if strings.Join(fields, " ") != strings.Join(want, " ") {
+			t.Errorf("OmitReadOnly %v: got fields %q, want %q", omitReadOnly, fields, want)
+		}
+	}
+}
+
+func TestCompile_sharedEnumTypes(t *testing.T) {
+	compile := func(data string) ([]ast.Decl, error) {
+		var schema *jsonschema.Schema
+		if err := json.Unmarshal([]byte(data), &schema); err != nil {
+			t.Fatal(err)
+		}
+		decls, _, err := CompileWithOptions([]*jsonschema.Schema{schema}, Options{EnumTypes: true})
+		return decls, err
+	}
+
+	decls, err := compile(`{"title": "a", "type": "object", "properties": {
+  "b": {"type": "object", "properties": {"mode": {"type": "string", "enum": ["x", "y"]}}},
+  "c": {"type": "object", "properties": {"mode": {"type": "string", "enum": ["x", "y"]}}}
+}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var modeTypes int
+	for _, decl := range decls {
+		if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.TYPE && d.Specs[0].(*ast.TypeSpec).Name.Name == "Mode" {
+			modeTypes++
+		}
+	}
+	if modeTypes != 1 {
+		t.Errorf("got %d Mode type declarations, want 1", modeTypes)
+	}
+
+	_, err = compile(`{"title": "a", "type": "object", "properties": {
+  "b": {"type": "object", "properties": {"mode": {"type": "string", "enum": ["x", "y"]}}},
+  "c": {"type": "object", "properties": {"mode": {"type": "string", "enum": ["x", "z"]}}}
+}}`)
+	if want := "enum type Mode is also declared with different values"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v, want it to contain %q", err, want)
+	}
+}
@@ -0,0 +1,63 @@
+package compiler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	testdata_enumtypes "github.com/sourcegraph/go-jsonschema/compiler/testdata/enum-types"
+)
+
+// TestEnumTypes depends on the generated ./testdata/enum-types/want.go file, which you can
+// overwrite with the latest generated code by running `go test -test.write-want`.
+func TestEnumTypes(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		want    testdata_enumtypes.Repository
+		wantErr string
+	}{
+		"valid": {
+			data: `{"gitURLType":"ssh","visibility":"","priority":2,"languages":["go","c++"]}`,
+			want: testdata_enumtypes.Repository{
+				GitURLType: testdata_enumtypes.GitURLTypeSsh,
+				Priority:   testdata_enumtypes.Priority_2,
+				Languages:  []testdata_enumtypes.Language{testdata_enumtypes.LanguageGo, testdata_enumtypes.LanguageC},
+			},
+		},
+		"unknown string": {
+			data:    `{"gitURLType":"git"}`,
+			wantErr: `invalid GitURLType value "git"`,
+		},
+		"unknown integer": {
+			data:    `{"gitURLType":"http","priority":4}`,
+			wantErr: "invalid Priority value 4",
+		},
+		"unknown array item": {
+			data:    `{"gitURLType":"http","languages":["go","rust"]}`,
+			wantErr: `invalid Language value "rust"`,
+		},
+	}
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			var got testdata_enumtypes.Repository
+			err := json.Unmarshal([]byte(test.data), &got)
+			var gotErr string
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != test.wantErr {
+				t.Fatalf("got error %q, want %q", gotErr, test.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+
+	if !testdata_enumtypes.VisibilityPrivate.IsValid() {
+		t.Error("VisibilityPrivate is not valid")
+	}
+	if testdata_enumtypes.Visibility("internal").IsValid() {
+		t.Error(`Visibility("internal") is valid`)
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"go/ast"
 	"go/printer"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
 type field struct {
 	GoName, JSONName string
 	*ast.Field
+
+	schema   *jsonschema.Schema // the schema of the property
+	required bool               // whether the property is required
 }
 
 func (f field) GoType() string {
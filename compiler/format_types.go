@@ -0,0 +1,71 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// A FormatType is a Go type that holds the values of string schemas with a "format" (see
+// Options.FormatTypes).
+type FormatType struct {
+	// Type is the Go type expression (such as "time.Time"). Values of the type must be encoded in
+	// JSON as strings (such as by implementing encoding.TextMarshaler and
+	// encoding.TextUnmarshaler).
+	Type string
+
+	// ImportPath is the import path of the package that Type refers to (such as "time"), or "" if
+	// none.
+	ImportPath string
+
+	// Nilable reports whether the zero value of the type represents an absent value (such as for
+	// slice types), so that the struct fields for optional properties need not be pointers.
+	Nilable bool
+}
+
+// DefaultFormatTypes are Go types for the values of formats that are richer than string. Types from
+// package formats (github.com/sourcegraph/go-jsonschema/formats) are used if the standard library
+// has no suitable type. To use them, add them to Options.FormatTypes.
+//
+// The "duration" format is not included, because formats.Duration can't hold durations in years or
+// months (which are valid values of the format). If a schema's durations never use them, add
+// DurationFormatType to Options.FormatTypes.
+var DefaultFormatTypes = map[string]FormatType{
+	"date-time": {Type: "time.Time", ImportPath: "time"},
+	"ipv4":      {Type: "net.IP", ImportPath: "net", Nilable: true},
+	"ipv6":      {Type: "net.IP", ImportPath: "net", Nilable: true},
+	"uri":       {Type: "formats.URI", ImportPath: formatsImportPath},
+	"uuid":      {Type: "formats.UUID", ImportPath: formatsImportPath},
+}
+
+// DurationFormatType is the Go type for the values of the "duration" format that are in weeks or in
+// days, hours, minutes and seconds (see formats.ParseDuration).
+var DurationFormatType = FormatType{Type: "formats.Duration", ImportPath: formatsImportPath}
+
+const formatsImportPath = "github.com/sourcegraph/go-jsonschema/formats"
+
+// formatType returns the Go type for the values of the string schema's "format" (if it has one
+// in g.formatTypes).
+func (g *generator) formatType(schema *jsonschema.Schema) (FormatType, bool) {
+	if len(schema.Type) != 1 || schema.Type[0] != jsonschema.StringType || schema.Format == nil {
+		return FormatType{}, false
+	}
+	typ, ok := g.formatTypes[string(*schema.Format)]
+	return typ, ok
+}
+
+// formatTypeExpr returns the Go expression AST node that refers to the Go type for the values of
+// the format, as well as the Go import statements that it needs.
+func formatTypeExpr(format jsonschema.Format, typ FormatType) (ast.Expr, []*ast.ImportSpec, error) {
+	x, err := parser.ParseExpr(typ.Type)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, fmt.Sprintf("invalid Go type for format %q", format))
+	}
+	if typ.ImportPath == "" {
+		return x, nil, nil
+	}
+	return x, importSpecs(typ.ImportPath), nil
+}
@@ -0,0 +1,46 @@
+package compiler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	testdata_formattypes "github.com/sourcegraph/go-jsonschema/compiler/testdata/format-types"
+)
+
+// TestFormatTypes depends on the generated ./testdata/format-types/want.go file, which you can
+// overwrite with the latest generated code by running `go test -test.write-want`.
+func TestFormatTypes(t *testing.T) {
+	const data = `{"clientIP":"192.0.2.1","id":"f81d4fae-7dec-11d0-a765-00a0c91e6bf6","serverIPs":["2001:db8::1"],"source":"https://example.com","time":"2018-11-13T20:20:39Z","timeout":"PT1M30S"}`
+	var v testdata_formattypes.Event
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2018, 11, 13, 20, 20, 39, 0, time.UTC); !v.Time.Equal(want) {
+		t.Errorf("got time %s, want %s", v.Time, want)
+	}
+	if v.EndTime != nil {
+		t.Errorf("got endTime %s, want nil", v.EndTime)
+	}
+	if want := 90 * time.Second; v.Timeout == nil || v.Timeout.Duration != want {
+		t.Errorf("got timeout %v, want %s", v.Timeout, want)
+	}
+	if v.Source == nil || v.Source.Host != "example.com" {
+		t.Errorf("got source %v, want host example.com", v.Source)
+	}
+	if v.ClientIP.To4() == nil || len(v.ServerIPs) != 1 || v.ServerIPs[0].To16() == nil {
+		t.Errorf("got IPs %v and %v", v.ClientIP, v.ServerIPs)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != data {
+		t.Errorf("got JSON %s, want %s", out, data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"id":"x","time":"2018-11-13T20:20:39Z"}`), &v); err == nil {
+		t.Error("got no error for invalid uuid")
+	}
+}
@@ -14,8 +14,11 @@ import (
 
 // generateDecls returns Go type declarations for the schemas, which are all in the same root JSON
 // Schema.
-func generateDecls(schemas map[*jsonschema.Schema]schemaLocation, resolutions map[*jsonschema.Schema]*jsonschema.Schema, schemaLocator schemaLocator) ([]ast.Decl, []*ast.ImportSpec, error) {
-	g := generator{schemas: schemas, resolutions: resolutions, schemaLocator: schemaLocator}
+//
+// The declarations of enum types are added to enumDecls (which is shared by all root schemas)
+// instead of returned.
+func generateDecls(schemas map[*jsonschema.Schema]schemaLocation, resolutions map[*jsonschema.Schema]*jsonschema.Schema, schemaLocator schemaLocator, enumDecls map[string]*enumDecl, opts Options) ([]ast.Decl, []*ast.ImportSpec, error) {
+	g := generator{schemas: schemas, resolutions: resolutions, schemaLocator: schemaLocator, enumDecls: enumDecls, documentOrder: opts.DocumentOrder, omitReadOnly: opts.OmitReadOnly, validateMethods: opts.ValidateMethods, enumTypes: opts.EnumTypes, strictEnums: opts.StrictEnums, formatTypes: opts.FormatTypes}
 	var allDecls []ast.Decl
 	var allImports []*ast.ImportSpec
 	for schema := range schemas {
@@ -30,11 +33,18 @@ func generateDecls(schemas map[*jsonschema.Schema]schemaLocation, resolutions ma
 }
 
 type generator struct {
-	schemas       map[*jsonschema.Schema]schemaLocation     // for the current root schema only
-	resolutions   map[*jsonschema.Schema]*jsonschema.Schema // for all schemas in scope
-	schemaLocator schemaLocator
+	schemas         map[*jsonschema.Schema]schemaLocation     // for the current root schema only
+	resolutions     map[*jsonschema.Schema]*jsonschema.Schema // for all schemas in scope
+	schemaLocator   schemaLocator
+	documentOrder   bool                  // emit struct fields in document order (see Options.DocumentOrder)
+	omitReadOnly    bool                  // omit read-only properties from struct types (see Options.OmitReadOnly)
+	validateMethods bool                  // emit Validate methods on struct types (see Options.ValidateMethods)
+	enumTypes       bool                  // emit named types for enums (see Options.EnumTypes)
+	strictEnums     bool                  // emit UnmarshalJSON methods on enum types (see Options.StrictEnums)
+	formatTypes     map[string]FormatType // Go types for formats (see Options.FormatTypes)
 
-	decls []ast.Decl
+	enumDecls map[string]*enumDecl // Go name -> enum type (for all root schemas)
+	decls     []ast.Decl
 }
 
 var emptyInterfaceType = &ast.InterfaceType{
@@ -52,9 +62,12 @@ func (g *generator) emit(schema *jsonschema.Schema) ([]ast.Decl, []*ast.ImportSp
 	if schema.Go != nil && schema.Go.TaggedUnionType {
 		return g.emitTaggedUnionType(schema)
 	}
+	if g.isEnumType(schema) {
+		// Enum types are declared after all schemas are generated (see enumDecl).
+		return nil, nil, g.addEnumType(schema)
+	}
 
-	needsNamedGoType := len(schema.Type) == 1 && schema.Type[0] == jsonschema.ObjectType && schema.Properties != nil
-	if !needsNamedGoType {
+	if !isEmittedAsGoStructType(schema) {
 		return nil, nil, nil
 	}
 
@@ -62,17 +75,25 @@ func (g *generator) emit(schema *jsonschema.Schema) ([]ast.Decl, []*ast.ImportSp
 }
 
 func (g *generator) emitStructType(schema *jsonschema.Schema) (decls []ast.Decl, imports []*ast.ImportSpec, err error) {
-	// Sort properties deterministically (by name).
-	names := make([]string, 0, len(*schema.Properties))
-	for name := range *schema.Properties {
-		names = append(names, name)
+	// Order properties deterministically (by name, or in document order).
+	var names []string
+	if g.documentOrder {
+		names = schema.OrderedKeys("properties")
+	} else {
+		names = make([]string, 0, len(*schema.Properties))
+		for name := range *schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 	}
-	sort.Strings(names)
 
 	// Create a field for each property.
-	fields := make([]field, len(names))
-	for i, name := range names {
+	fields := make([]field, 0, len(names))
+	for _, name := range names {
 		prop := (*schema.Properties)[name]
+		if g.omitReadOnly && isReadOnly(prop) {
+			continue
+		}
 
 		typeExpr, fieldImports, err := g.expr(prop)
 		if err != nil {
@@ -80,21 +101,24 @@ func (g *generator) emitStructType(schema *jsonschema.Schema) (decls []ast.Decl,
 		}
 		imports = append(imports, fieldImports...)
 
+		required := schema.IsRequiredProperty(name)
 		var jsonStructTagExtra string
-		if !schema.IsRequiredProperty(name) {
+		if !required {
 			// In Go, a pointer-to-{array,map,interface}-type doesn't add (necessary) expressiveness for our use
 			// case vs. just an {array,map,interface} type.
 			_, isPtrToArray := typeExpr.(*ast.ArrayType)
 			_, isPtrToMap := typeExpr.(*ast.MapType)
 			_, isPtrToInterface := typeExpr.(*ast.InterfaceType)
-			if (!isPtrToArray && !isPtrToMap && !isPtrToInterface && !isBasicType(typeExpr)) || forceGoPointer(prop) {
+			isEnum := g.isEnumType(g.resolve(prop))
+			formatType, _ := g.formatType(g.resolve(prop))
+			if (!isPtrToArray && !isPtrToMap && !isPtrToInterface && !isBasicType(typeExpr) && !isEnum && !formatType.Nilable) || forceGoPointer(prop) {
 				typeExpr = &ast.StarExpr{X: typeExpr}
 			}
 			jsonStructTagExtra = ",omitempty"
 		}
 
 		goName := toGoName(name, "Property_")
-		fields[i] = field{
+		fields = append(fields, field{
 			GoName:   goName,
 			JSONName: name,
 			Field: &ast.Field{
@@ -104,8 +128,11 @@ func (g *generator) emitStructType(schema *jsonschema.Schema) (decls []ast.Decl,
 					Kind:  token.STRING,
 					Value: fmt.Sprintf("`json:%q`", name+jsonStructTagExtra),
 				},
+				Comment: commentForProperty(prop),
 			},
-		}
+			schema:   prop,
+			required: required,
+		})
 	}
 
 	goName, err := goNameForSchema(schema, g.schemas[schema])
@@ -135,6 +162,15 @@ func (g *generator) emitStructType(schema *jsonschema.Schema) (decls []ast.Decl,
 
 	}
 
+	if g.validateMethods {
+		decls1, imports1, err := g.emitValidateMethods(goName, fields, false)
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, fmt.Sprintf("failed to emit Validate method for %s", goName))
+		}
+		decls = append(decls, decls1...)
+		imports = append(imports, imports1...)
+	}
+
 	return decls, imports, nil
 }
 
@@ -164,7 +200,8 @@ func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec
 			//
 			// TODO(sqs): Not all $ref values point to things that are Go named types.
 			useGoTaggedUnionType := schema.Items.Schema.Go != nil && schema.Items.Schema.Go.TaggedUnionType
-			if (isEmittedAsGoNamedType(schema.Items.Schema) || schema.Items.Schema.Reference != nil) && !useGoTaggedUnionType {
+			isEnum := g.isEnumType(g.resolve(schema.Items.Schema))
+			if (isEmittedAsGoNamedType(schema.Items.Schema) || schema.Items.Schema.Reference != nil) && !useGoTaggedUnionType && !isEnum {
 				elt = &ast.StarExpr{X: elt}
 			}
 		} else {
@@ -186,6 +223,16 @@ func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec
 	if len(schema.Type) != 1 && (schema.Go == nil || !schema.Go.TaggedUnionType) {
 		return emptyInterfaceType, nil, nil
 	}
+	if g.isEnumType(schema) {
+		return g.namedTypeExpr(schema)
+	}
+	if formatType, ok := g.formatType(schema); ok {
+		return formatTypeExpr(*schema.Format, formatType)
+	}
+	if len(schema.Type) == 1 && schema.Type[0] == jsonschema.StringType && isBase64Encoded(schema) {
+		// encoding/json encodes []byte values as base64 strings.
+		return &ast.ArrayType{Elt: ast.NewIdent("byte")}, nil, nil
+	}
 	if len(schema.Type) == 1 && goBuiltinType(schema.Type[0]) != "" {
 		return ast.NewIdent(goBuiltinType(schema.Type[0])), nil, nil
 	}
@@ -197,9 +244,14 @@ func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec
 	}
 
 	// Otherwise, use a Go named type.
+	return g.namedTypeExpr(schema)
+}
+
+// namedTypeExpr returns the Go expression AST node that refers to the Go named type for schema.
+func (g *generator) namedTypeExpr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec, error) {
 	_, location := g.schemaLocator.locateSchema(schema)
 	if location == nil {
-		return nil, nil, errors.New("unable to locate schema")
+		return nil, nil, jsonschema.ErrorAt(schema.Pos, errors.New("unable to locate schema"))
 	}
 	goName, err := goNameForSchema(schema, *location)
 	if err != nil {
@@ -208,6 +260,15 @@ func (g *generator) expr(schema *jsonschema.Schema) (ast.Expr, []*ast.ImportSpec
 	return ast.NewIdent(goName), nil, nil
 }
 
+// resolve returns the schema that schema refers to with "$ref" (following chains of references),
+// or schema itself if it has no "$ref".
+func (g *generator) resolve(schema *jsonschema.Schema) *jsonschema.Schema {
+	for schema != nil && schema != metaSchemaSentinel && schema.Reference != nil {
+		schema = g.resolutions[schema]
+	}
+	return schema
+}
+
 func docForSchema(schema *jsonschema.Schema, goName string) *ast.CommentGroup {
 	if schema.Description == nil {
 		return nil
@@ -221,6 +282,21 @@ func docForSchema(schema *jsonschema.Schema, goName string) *ast.CommentGroup {
 	}
 }
 
+// commentForProperty returns the line comment for the struct field for a property, which notes
+// whether the property is read-only or write-only.
+func commentForProperty(schema *jsonschema.Schema) *ast.CommentGroup {
+	var text string
+	switch {
+	case isReadOnly(schema):
+		text = "// read-only"
+	case schema.WriteOnly != nil && *schema.WriteOnly:
+		text = "// write-only"
+	default:
+		return nil
+	}
+	return &ast.CommentGroup{List: []*ast.Comment{{Text: text}}}
+}
+
 func lineComments(s string) string {
 	var buf bytes.Buffer
 	buf.WriteString("// ")
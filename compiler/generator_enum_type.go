@@ -0,0 +1,189 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"text/template"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// enumValues returns the Go literals of the values of the schema's "enum" (without duplicates), if
+// the schema is a string or integer schema whose enum values are all of its type. Otherwise it
+// returns nil.
+func enumValues(schema *jsonschema.Schema) []string {
+	if len(schema.Type) != 1 || (schema.Type[0] != jsonschema.StringType && schema.Type[0] != jsonschema.IntegerType) {
+		return nil
+	}
+	var lits []string
+	seen := map[string]bool{}
+	for _, value := range schema.Enum {
+		var lit string
+		switch value := value.(type) {
+		case string:
+			if schema.Type[0] != jsonschema.StringType {
+				return nil
+			}
+			lit = strconv.Quote(value)
+		case float64:
+			if schema.Type[0] != jsonschema.IntegerType || !isIntegral(value) {
+				return nil
+			}
+			lit = strconv.FormatInt(int64(value), 10)
+		default:
+			return nil
+		}
+		if !seen[lit] {
+			lits = append(lits, lit)
+			seen[lit] = true
+		}
+	}
+	return lits
+}
+
+// isEnumType reports whether a named Go type with constants is declared for the schema (see
+// Options.EnumTypes). Enums with a single value (such as the discriminant properties of tagged
+// union types) are represented by the Go builtin type.
+func (g *generator) isEnumType(schema *jsonschema.Schema) bool {
+	return g.enumTypes && len(enumValues(schema)) >= 2
+}
+
+// An enumDecl holds the declarations of an enum type. Enum schemas that have the same Go name and
+// the same values (such as the same property of several object schemas) share an enum type.
+type enumDecl struct {
+	schema  *jsonschema.Schema // the schema that the declarations were generated for
+	key     string             // orders the schemas that share the enum type (the first is used)
+	values  []string           // the Go literals of the enum values
+	decls   []ast.Decl
+	imports []*ast.ImportSpec
+}
+
+// addEnumType generates the declarations of the enum type for the schema and adds them to
+// g.enumDecls (unless they are already there, for another schema that shares the enum type).
+func (g *generator) addEnumType(schema *jsonschema.Schema) error {
+	location := g.schemas[schema]
+	goName, err := goNameForSchema(schema, location)
+	if err != nil {
+		return err
+	}
+	key := jsonschema.EncodeReferenceTokens(location.rel)
+	if schema.Description != nil {
+		key += "\x00" + *schema.Description
+	}
+	values := enumValues(schema)
+	if other, ok := g.enumDecls[goName]; ok {
+		if !reflect.DeepEqual(other.values, values) || other.schema.Type[0] != schema.Type[0] {
+			return jsonschema.ErrorAt(schema.Pos, fmt.Errorf("enum type %s is also declared with different values (give one of the schemas a title)", goName))
+		}
+		if other.key <= key {
+			return nil
+		}
+	}
+
+	decls, imports, err := g.emitEnumType(schema, goName, values)
+	if err != nil {
+		return err
+	}
+	g.enumDecls[goName] = &enumDecl{schema: schema, key: key, values: values, decls: decls, imports: imports}
+	return nil
+}
+
+// emitEnumType returns the declarations of the named Go type for the string or integer enum
+// schema, its constants (one for each of the values) and its methods.
+func (g *generator) emitEnumType(schema *jsonschema.Schema, goName string, values []string) ([]ast.Decl, []*ast.ImportSpec, error) {
+	underlying := goBuiltinType(schema.Type[0])
+
+	// Name each constant after its value (or, if that doesn't produce a unique name, its index).
+	constDecl := &ast.GenDecl{Tok: token.CONST, Lparen: 1, Rparen: 1}
+	constNames := make([]string, len(values))
+	seen := map[string]bool{goName: true}
+	for i, value := range values {
+		s := value
+		if underlying == "string" {
+			s, _ = strconv.Unquote(value)
+		}
+		name := goName + toGoName(s, "_")
+		if seen[name] || name == goName+"_" {
+			name = goName + "_" + strconv.Itoa(i)
+		}
+		seen[name] = true
+		constNames[i] = name
+		constDecl.Specs = append(constDecl.Specs, &ast.ValueSpec{
+			Names:  []*ast.Ident{ast.NewIdent(name)},
+			Type:   ast.NewIdent(goName),
+			Values: []ast.Expr{&ast.BasicLit{Kind: enumLitKind(underlying), Value: value}},
+		})
+	}
+
+	decls := []ast.Decl{
+		&ast.GenDecl{
+			Doc:   docForSchema(schema, goName),
+			Tok:   token.TYPE,
+			Specs: []ast.Spec{&ast.TypeSpec{Name: ast.NewIdent(goName), Type: ast.NewIdent(underlying)}},
+		},
+		constDecl,
+	}
+
+	// Generate the IsValid method (and, for strict enums, the UnmarshalJSON method).
+	verb := "%d"
+	if underlying == "string" {
+		verb = "%q"
+	}
+	templateData := map[string]interface{}{
+		"goName":      goName,
+		"underlying":  underlying,
+		"constNames":  constNames,
+		"errorFormat": fmt.Sprintf("invalid %s value %s", goName, verb),
+	}
+	isValidDecl, err := parseFuncLitToFuncDecl(executeTemplate(enumTypeIsValidTemplate, templateData))
+	if err != nil {
+		return nil, nil, err
+	}
+	makeMethod(isValidDecl, ast.NewIdent(goName), "IsValid")
+	decls = append(decls, isValidDecl)
+	if !g.strictEnums {
+		return decls, nil, nil
+	}
+	unmarshalJSONDecl, err := parseFuncLitToFuncDecl(executeTemplate(enumTypeUnmarshalJSONTemplate, templateData))
+	if err != nil {
+		return nil, nil, err
+	}
+	makeMethod(unmarshalJSONDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "UnmarshalJSON")
+	decls = append(decls, unmarshalJSONDecl)
+	return decls, importSpecs("encoding/json", "fmt"), nil
+}
+
+func enumLitKind(underlying string) token.Token {
+	if underlying == "string" {
+		return token.STRING
+	}
+	return token.INT
+}
+
+var (
+	enumTypeIsValidTemplate = template.Must(template.New("").Parse(`
+func() bool {
+	switch v {
+	case {{range $i, $name := .constNames}}{{if $i}}, {{end}}{{$name}}{{end}}:
+		return true
+	}
+	return false
+}
+`))
+	enumTypeUnmarshalJSONTemplate = template.Must(template.New("").Parse(`
+func(data []byte) error {
+	var x {{.underlying}}
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	if !{{.goName}}(x).IsValid() {
+		return fmt.Errorf({{printf "%q" .errorFormat}}, x)
+	}
+	*v = {{.goName}}(x)
+	return nil
+}
+`))
+)
@@ -87,6 +87,8 @@ func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl,
 			return nil, nil, fmt.Errorf("no oneOf schema discriminant prop enum value for !go.taggedUnionType extension (must have either const or enum)")
 		}
 		switch ev := (*constVal).(type) {
+		case nil:
+			return nil, nil, fmt.Errorf("invalid oneOf schema discriminant prop const value for !go.taggedUnionType extension (discriminant property %q must not be null)", discriminantPropName)
 		case string:
 			if _, seen := constValuesToSchema[ev]; seen {
 				return nil, nil, fmt.Errorf("invalid oneOf schema discriminant prop const value for !go.taggedUnionType extension (value %q is allowed by other type)", ev)
@@ -148,10 +150,23 @@ func (g *generator) emitTaggedUnionType(schema *jsonschema.Schema) ([]ast.Decl,
 	}
 	makeMethod(marshalJSONDecl, ast.NewIdent(goName), "MarshalJSON")
 	makeMethod(unmarshalJSONDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "UnmarshalJSON")
+	decls := []ast.Decl{typeDecl, marshalJSONDecl, unmarshalJSONDecl}
 
-	return []ast.Decl{typeDecl, marshalJSONDecl, unmarshalJSONDecl},
-		imports,
-		nil
+	// The Validate method validates the value of the non-nil field.
+	if g.validateMethods {
+		validateFields := make([]field, len(fields))
+		for i, f := range fields {
+			validateFields[i] = field{GoName: fieldNames[i], Field: f, schema: oneOfSchemas[i]}
+		}
+		decls1, imports1, err := g.emitValidateMethods(goName, validateFields, true)
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, fmt.Sprintf("failed to emit Validate method for %s", goName))
+		}
+		decls = append(decls, decls1...)
+		imports = append(imports, imports1...)
+	}
+
+	return decls, imports, nil
 }
 
 var (
@@ -0,0 +1,414 @@
+package compiler
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// emitValidateMethods returns the Validate and validateAt methods of the named Go type (whose
+// fields are given), as well as the declarations of the package-level variables that they use. If
+// taggedUnion is true, the Go type is a tagged union type, whose fields hold the whole value (not
+// the values of properties).
+//
+// The generated validateAt method checks the schema constraints of the fields' values in plain Go
+// code and returns an error (prefixed with the JSON Pointer of the invalid value relative to path)
+// for the first constraint that is not satisfied. Validate calls validateAt for the whole value.
+func (g *generator) emitValidateMethods(goName string, fields []field, taggedUnion bool) ([]ast.Decl, []*ast.ImportSpec, error) {
+	w := validateWriter{g: g, goName: goName, taggedUnion: taggedUnion, buf: new(bytes.Buffer), imports: map[string]struct{}{}}
+	for _, f := range fields {
+		if err := w.field(f); err != nil {
+			return nil, nil, errors.WithMessage(err, fmt.Sprintf("failed to generate validation for property %q", f.JSONName))
+		}
+	}
+
+	validateDecl, err := parseFuncLitToFuncDecl(`func() error { return v.validateAt("") }`)
+	if err != nil {
+		return nil, nil, err
+	}
+	validateAtDecl, err := parseFuncLitToFuncDecl("func(path string) error {\n" + w.buf.String() + "return nil\n}")
+	if err != nil {
+		return nil, nil, err
+	}
+	makeMethod(validateDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "Validate")
+	makeMethod(validateAtDecl, &ast.StarExpr{X: ast.NewIdent(goName)}, "validateAt")
+	decls := []ast.Decl{validateDecl, validateAtDecl}
+
+	// Compile the patterns and parse the decimal multipleOf values once, when the package is
+	// initialized.
+	varDecl := &ast.GenDecl{Tok: token.VAR}
+	for i, pattern := range w.patterns {
+		value, err := parser.ParseExpr("regexp.MustCompile(" + strconv.Quote(pattern) + ")")
+		if err != nil {
+			return nil, nil, err
+		}
+		varDecl.Specs = append(varDecl.Specs, &ast.ValueSpec{
+			Names:  []*ast.Ident{ast.NewIdent(w.patternVar(i))},
+			Values: []ast.Expr{value},
+		})
+	}
+	for i, rat := range w.rats {
+		value, err := parser.ParseExpr("new(big.Rat).SetString(" + strconv.Quote(rat) + ")")
+		if err != nil {
+			return nil, nil, err
+		}
+		varDecl.Specs = append(varDecl.Specs, &ast.ValueSpec{
+			Names:  []*ast.Ident{ast.NewIdent(w.ratVar(i)), ast.NewIdent("_")},
+			Values: []ast.Expr{value},
+		})
+	}
+	if len(varDecl.Specs) > 0 {
+		if len(varDecl.Specs) > 1 {
+			varDecl.Lparen = 1
+			varDecl.Rparen = 1
+		}
+		decls = append(decls, varDecl)
+	}
+
+	paths := make([]string, 0, len(w.imports))
+	for path := range w.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return decls, importSpecs(paths...), nil
+}
+
+// validateWriter writes the body of a validateAt method.
+type validateWriter struct {
+	g           *generator
+	goName      string // the name of the Go type that the method is declared on
+	taggedUnion bool   // whether the Go type is a tagged union type
+
+	buf      *bytes.Buffer
+	imports  map[string]struct{} // import paths used by the written code
+	patterns []string            // regular expressions compiled into package-level variables
+	rats     []string            // decimal numbers parsed into package-level *big.Rat variables
+	vars     int                 // the number of variable name suffixes returned by newSuffix
+}
+
+// field writes the checks for the value of a struct field. An optional field whose Go type can't
+// represent absence (such as a string field with omitempty) is only checked if it is not the zero
+// value.
+func (w *validateWriter) field(f field) error {
+	x := "v." + f.GoName
+	path := pathParam
+	if !w.taggedUnion {
+		path = appendPathToken(path, escapeReferenceToken(f.JSONName), "")
+	}
+	if f.required {
+		return w.value(f.schema, x, f.Type, path)
+	}
+	var cond string
+	switch typ := f.Type.(type) {
+	case *ast.Ident:
+		underlying := typ.Name
+		if schema := w.g.resolve(f.schema); w.g.isEnumType(schema) {
+			underlying = goBuiltinType(schema.Type[0])
+		}
+		switch underlying {
+		case "string":
+			cond = x + ` != ""`
+		case "int", "float64":
+			cond = x + " != 0"
+		default:
+			return nil
+		}
+	case *ast.ArrayType, *ast.MapType:
+		cond = x + " != nil"
+	default:
+		return w.value(f.schema, x, f.Type, path)
+	}
+	return w.block("if "+cond, func() error { return w.value(f.schema, x, f.Type, path) })
+}
+
+// value writes the checks for x, a Go expression of type typ that holds an instance of schema at
+// the JSON Pointer given by the Go expression path.
+func (w *validateWriter) value(schema *jsonschema.Schema, x string, typ ast.Expr, path string) error {
+	schema = w.g.resolve(schema)
+	if schema == nil || schema == metaSchemaSentinel {
+		return nil
+	}
+
+	if star, ok := typ.(*ast.StarExpr); ok {
+		if isEmittedAsGoStructType(schema) {
+			return w.block("if "+x+" != nil", func() error { return w.value(schema, x, star.X, path) })
+		}
+		return w.block("if "+x+" != nil", func() error { return w.value(schema, "*"+x, star.X, path) })
+	}
+	if isEmittedAsGoStructType(schema) {
+		fmt.Fprintf(w.buf, "if err := %s.validateAt(%s); err != nil {\nreturn err\n}\n", x, path)
+		return nil
+	}
+	if w.g.isEnumType(schema) {
+		// Check the enum with the enum type's IsValid method, and check the other constraints of
+		// the value of the underlying Go type.
+		w.fail(fmt.Sprintf("!%s.IsValid()", parenthesize(x)), path, "value is not one of the allowed enum values")
+		underlying := *schema
+		underlying.Enum = nil
+		schema = &underlying
+		typ = ast.NewIdent(goBuiltinType(schema.Type[0]))
+		x = typ.(*ast.Ident).Name + "(" + x + ")"
+	}
+
+	switch typ := typ.(type) {
+	case *ast.ArrayType:
+		if elt, ok := typ.Elt.(*ast.Ident); ok && elt.Name == "byte" {
+			return nil // a base64-encoded string
+		}
+		return w.array(schema, x, typ, path)
+	case *ast.MapType:
+		return w.object(schema, x, typ, path)
+	case *ast.Ident:
+		switch typ.Name {
+		case "string":
+			return w.string(schema, x, path)
+		case "int", "float64":
+			return w.number(schema, x, typ.Name, path)
+		}
+	}
+	return nil
+}
+
+func (w *validateWriter) string(schema *jsonschema.Schema, x, path string) error {
+	w.enum(schema, x, path, func(value interface{}) (string, bool) {
+		s, ok := value.(string)
+		return strconv.Quote(s), ok
+	})
+	if schema.MaxLength != nil {
+		w.imports["unicode/utf8"] = struct{}{}
+		w.fail(fmt.Sprintf("utf8.RuneCountInString(%s) > %d", x, *schema.MaxLength), path, fmt.Sprintf("string is longer than the maximum length %d", *schema.MaxLength))
+	}
+	if schema.MinLength != nil {
+		w.imports["unicode/utf8"] = struct{}{}
+		w.fail(fmt.Sprintf("utf8.RuneCountInString(%s) < %d", x, *schema.MinLength), path, fmt.Sprintf("string is shorter than the minimum length %d", *schema.MinLength))
+	}
+	if schema.Pattern != nil {
+		if _, err := regexp.Compile(*schema.Pattern); err != nil {
+			return jsonschema.ErrorAt(schema.KeywordPos["pattern"], errors.WithMessage(err, "invalid pattern"))
+		}
+		w.imports["regexp"] = struct{}{}
+		w.patterns = append(w.patterns, *schema.Pattern)
+		w.fail(fmt.Sprintf("!%s.MatchString(%s)", w.patternVar(len(w.patterns)-1), x), path, escapePercent(fmt.Sprintf("string does not match the pattern %q", *schema.Pattern)))
+	}
+	return nil
+}
+
+func (w *validateWriter) number(schema *jsonschema.Schema, x, goType, path string) error {
+	w.enum(schema, x, path, func(value interface{}) (string, bool) {
+		f, ok := value.(float64)
+		if !ok || (goType == "int" && !isIntegral(f)) {
+			return "", false
+		}
+		return formatFloat(f), true
+	})
+	if m := schema.MultipleOf; m != nil {
+		if *m <= 0 {
+			return jsonschema.ErrorAt(schema.KeywordPos["multipleOf"], fmt.Errorf("multipleOf must be greater than 0 (got %v)", *m))
+		}
+		if goType == "int" && isIntegral(*m) {
+			operand := strconv.FormatInt(int64(*m), 10)
+			w.fail(fmt.Sprintf("%s%%%s != 0", x, operand), path, "%v is not a multiple of "+operand, x)
+		} else {
+			// As in the jsonschema package's validator, compare the decimal values (as they are
+			// written in JSON) exactly, so that 0.3 is a multiple of 0.1.
+			w.imports["math/big"] = struct{}{}
+			w.imports["strconv"] = struct{}{}
+			w.rats = append(w.rats, formatFloat(*m))
+			cond := fmt.Sprintf("r, ok := new(big.Rat).SetString(strconv.FormatFloat(%s, 'g', -1, 64)); !ok || !r.Quo(r, %s).IsInt()", asFloat64(x, goType), w.ratVar(len(w.rats)-1))
+			w.fail(cond, path, "%v is not a multiple of "+formatFloat(*m), x)
+		}
+	}
+	compare := func(op string, bound float64, format string) {
+		lhs := x
+		if goType == "int" && !isIntegral(bound) {
+			lhs = asFloat64(x, goType)
+		}
+		w.fail(fmt.Sprintf("%s %s %s", lhs, op, formatFloat(bound)), path, format+formatFloat(bound), x)
+	}
+	if schema.Maximum != nil {
+		compare(">", *schema.Maximum, "%v is greater than the maximum ")
+	}
+	if schema.ExclusiveMaximum != nil {
+		compare(">=", *schema.ExclusiveMaximum, "%v is not less than the exclusive maximum ")
+	}
+	if schema.Minimum != nil {
+		compare("<", *schema.Minimum, "%v is less than the minimum ")
+	}
+	if schema.ExclusiveMinimum != nil {
+		compare("<=", *schema.ExclusiveMinimum, "%v is not greater than the exclusive minimum ")
+	}
+	return nil
+}
+
+// enum writes the "enum" and "const" checks for x. The literal function returns the Go literal
+// for an allowed value, or false if the value can't be held by x (such as a string value, when x is
+// a number); such values are ignored.
+func (w *validateWriter) enum(schema *jsonschema.Schema, x, path string, literal func(value interface{}) (string, bool)) {
+	var conds []string
+	seen := map[string]bool{}
+	for _, value := range schema.Enum {
+		if lit, ok := literal(value); ok && !seen[lit] {
+			conds = append(conds, x+" != "+lit)
+			seen[lit] = true
+		}
+	}
+	if len(conds) > 0 {
+		w.fail(strings.Join(conds, " && "), path, "value is not one of the allowed enum values")
+	}
+	if schema.Const != nil {
+		if lit, ok := literal(*schema.Const); ok {
+			w.fail(x+" != "+lit, path, "value is not equal to the const value")
+		}
+	}
+}
+
+func (w *validateWriter) array(schema *jsonschema.Schema, x string, typ *ast.ArrayType, path string) error {
+	if schema.MaxItems != nil {
+		w.fail(fmt.Sprintf("len(%s) > %d", x, *schema.MaxItems), path, fmt.Sprintf("array has more than the maximum %d items", *schema.MaxItems))
+	}
+	if schema.MinItems != nil {
+		w.fail(fmt.Sprintf("len(%s) < %d", x, *schema.MinItems), path, fmt.Sprintf("array has fewer than the minimum %d items", *schema.MinItems))
+	}
+	if schema.UniqueItems != nil && *schema.UniqueItems {
+		w.imports["fmt"] = struct{}{}
+		n := w.newSuffix()
+		i, j, seen := "i"+n, "j"+n, "seen"+n
+		const message = `"#%s: array items %d and %d are equal, but items must be unique"`
+		if isBasicType(typ.Elt) {
+			e := "e" + n
+			fmt.Fprintf(w.buf, "%s := make(map[%s]int, len(%s))\n", seen, typ.Elt.(*ast.Ident).Name, x)
+			fmt.Fprintf(w.buf, "for %s, %s := range %s {\n", i, e, x)
+			fmt.Fprintf(w.buf, "if %s, ok := %s[%s]; ok {\nreturn fmt.Errorf(%s, %s, %s, %s)\n}\n", j, seen, e, message, path, j, i)
+			fmt.Fprintf(w.buf, "%s[%s] = %s\n}\n", seen, e, i)
+		} else {
+			w.imports["reflect"] = struct{}{}
+			xi := parenthesize(x)
+			fmt.Fprintf(w.buf, "for %s := range %s {\n", i, x)
+			fmt.Fprintf(w.buf, "for %s := %s + 1; %s < len(%s); %s++ {\n", j, i, j, x, j)
+			fmt.Fprintf(w.buf, "if reflect.DeepEqual(%s[%s], %s[%s]) {\nreturn fmt.Errorf(%s, %s, %s, %s)\n}\n}\n}\n", xi, i, xi, j, message, path, i, j)
+		}
+	}
+	if schema.Items != nil && schema.Items.Schema != nil {
+		n := w.newSuffix()
+		i, e := "i"+n, "e"+n
+		return w.block(fmt.Sprintf("for %s, %s := range %s", i, e, x), func() error {
+			return w.value(schema.Items.Schema, e, typ.Elt, appendPathToken(path, "", "strconv.Itoa("+i+")"))
+		}, "strconv")
+	}
+	return nil
+}
+
+func (w *validateWriter) object(schema *jsonschema.Schema, x string, typ *ast.MapType, path string) error {
+	if schema.MaxProperties != nil {
+		w.fail(fmt.Sprintf("len(%s) > %d", x, *schema.MaxProperties), path, fmt.Sprintf("object has more than the maximum %d properties", *schema.MaxProperties))
+	}
+	if schema.MinProperties != nil {
+		w.fail(fmt.Sprintf("len(%s) < %d", x, *schema.MinProperties), path, fmt.Sprintf("object has fewer than the minimum %d properties", *schema.MinProperties))
+	}
+	if schema.AdditionalProperties != nil {
+		n := w.newSuffix()
+		k, e := "k"+n, "e"+n
+		key := fmt.Sprintf(`strings.Replace(strings.Replace(%s, "~", "~0", -1), "/", "~1", -1)`, k)
+		return w.block(fmt.Sprintf("for %s, %s := range %s", k, e, x), func() error {
+			return w.value(schema.AdditionalProperties, e, typ.Value, appendPathToken(path, "", key))
+		}, "strings")
+	}
+	return nil
+}
+
+// fail writes a check that returns an error if cond is true. The error message is format (with
+// its verbs replaced by the values of the Go expressions args), prefixed with the JSON Pointer path.
+func (w *validateWriter) fail(cond, path, format string, args ...string) {
+	w.imports["fmt"] = struct{}{}
+	fmt.Fprintf(w.buf, "if %s {\nreturn fmt.Errorf(%s)\n}\n", cond, strings.Join(append([]string{strconv.Quote("#%s: " + format), path}, args...), ", "))
+}
+
+// block writes the statement header (such as an if condition) followed by the block of statements
+// written by body, and it adds the given imports (which the block uses). Nothing is written if body
+// writes no statements.
+func (w *validateWriter) block(header string, body func() error, imports ...string) error {
+	outer := w.buf
+	w.buf = new(bytes.Buffer)
+	err := body()
+	inner := w.buf
+	w.buf = outer
+	if inner.Len() > 0 {
+		fmt.Fprintf(w.buf, "%s {\n%s}\n", header, inner)
+		for _, path := range imports {
+			w.imports[path] = struct{}{}
+		}
+	}
+	return err
+}
+
+// newSuffix returns a new suffix for the names of variables (so that they are unique in the
+// method).
+func (w *validateWriter) newSuffix() string {
+	w.vars++
+	return strconv.Itoa(w.vars)
+}
+
+// patternVar returns the name of the package-level variable that holds the i'th compiled pattern.
+func (w *validateWriter) patternVar(i int) string {
+	return "pattern" + w.goName + strconv.Itoa(i)
+}
+
+// ratVar returns the name of the package-level variable that holds the i'th parsed decimal number.
+func (w *validateWriter) ratVar(i int) string {
+	return "multipleOf" + w.goName + strconv.Itoa(i)
+}
+
+// pathParam is the parameter of the validateAt method that holds the JSON Pointer of the value.
+const pathParam = "path"
+
+// appendPathToken returns a Go expression for the JSON Pointer path with a reference token
+// appended. The token is the (escaped) literal token, followed by the value of the Go string
+// expression dynamic (if any).
+func appendPathToken(path, token, dynamic string) string {
+	lit := strconv.Quote("/" + token)
+	if strings.HasSuffix(path, `"`) {
+		path = path[:len(path)-1] + lit[1:]
+	} else {
+		path += "+" + lit
+	}
+	if dynamic != "" {
+		path += "+" + dynamic
+	}
+	return path
+}
+
+// escapeReferenceToken escapes "~" and "/" in a JSON Pointer reference token.
+func escapeReferenceToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func escapePercent(s string) string { return strings.Replace(s, "%", "%%", -1) }
+
+func parenthesize(x string) string {
+	if strings.HasPrefix(x, "*") {
+		return "(" + x + ")"
+	}
+	return x
+}
+
+func asFloat64(x, goType string) string {
+	if goType == "float64" {
+		return x
+	}
+	return "float64(" + x + ")"
+}
+
+func isIntegral(f float64) bool { return f == math.Trunc(f) && math.Abs(f) < 1<<53 }
+
+// formatFloat returns the shortest Go literal for f (which is also how fmt formats it with %v).
+func formatFloat(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
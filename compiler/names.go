@@ -25,7 +25,7 @@ func goNameForSchema(schema *jsonschema.Schema, location schemaLocation) (string
 		}
 	}
 	if name == "" {
-		return "", fmt.Errorf("schema at %q has no viable name", jsonschema.EncodeReferenceTokens(location.rel))
+		return "", jsonschema.ErrorAt(schema.Pos, fmt.Errorf("schema at %q has no viable name", jsonschema.EncodeReferenceTokens(location.rel)))
 	}
 
 	return toGoName(name, "Schema_"), nil
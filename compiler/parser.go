@@ -12,14 +12,16 @@ type schemaLocation struct {
 }
 
 // parseSchema parses the root JSON Schema, walking it recursively to record each (sub)schema's
-// relative location (from the root schema).
+// relative location (from the root schema). If enumTypes is true, enum schemas are recorded even
+// if they are otherwise trivial (see Options.EnumTypes).
 //
 // It returns a map of each (sub)schema to its relative location.
-func parseSchema(root *jsonschema.Schema) (map[*jsonschema.Schema]schemaLocation, error) {
+func parseSchema(root *jsonschema.Schema, enumTypes bool) (map[*jsonschema.Schema]schemaLocation, error) {
 	var err error
 	v := locationVisitor{
 		locations: map[*jsonschema.Schema]schemaLocation{},
 		err:       &err,
+		enumTypes: enumTypes,
 	}
 	jsonschema.Walk(&v, root)
 	return v.locations, err
@@ -29,6 +31,7 @@ func parseSchema(root *jsonschema.Schema) (map[*jsonschema.Schema]schemaLocation
 type locationVisitor struct {
 	locations map[*jsonschema.Schema]schemaLocation
 	err       *error
+	enumTypes bool // whether enums are emitted as Go named types (see Options.EnumTypes)
 
 	location schemaLocation
 }
@@ -40,19 +43,21 @@ func (v *locationVisitor) Visit(schema *jsonschema.Schema, rel []jsonschema.Refe
 	}
 
 	// TODO(sqs): Don't walk if/then/else because we're not validating, and those are usually only
-	// used for validation (not for defining types).
+	// used for validation (not for defining types). The same applies to "not", whose schema
+	// describes the values that are invalid.
 	if len(rel) > 0 {
-		if t := rel[len(rel)-1]; t.Keyword && (t.Name == "if" || t.Name == "then" || t.Name == "else") {
+		if t := rel[len(rel)-1]; t.Keyword && (t.Name == "if" || t.Name == "then" || t.Name == "else" || t.Name == "not") {
 			return nil
 		}
 	}
 
-	// Skip trivial schemas.
+	// Skip trivial schemas. With Options.EnumTypes, enums are not trivial, because they may be
+	// emitted as Go named types.
 	//
 	// TODO(sqs): The ref-to-primitive test case demonstrates a downside to this simple filter: some
 	// schemas must have a description for them to be $ref'd. Make this (and/or the resolution
 	// logic) smarter.
-	if schema.IsEmpty || schema.IsNegated || (len(schema.Type) == 1 && schema.Description == nil && goBuiltinType(schema.Type[0]) != "") {
+	if schema.IsEmpty || schema.IsNegated || (len(schema.Type) == 1 && schema.Description == nil && goBuiltinType(schema.Type[0]) != "" && (!v.enumTypes || len(schema.Enum) == 0)) {
 		return nil
 	}
 
@@ -48,7 +48,7 @@ func TestParseSchema(t *testing.T) {
 		},
 	}
 
-	locations, err := parseSchema(schemaRoot)
+	locations, err := parseSchema(schemaRoot, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -97,3 +97,24 @@ func TestParseSchema(t *testing.T) {
 }
 
 func strptr(s string) *string { return &s }
+
+func TestParseSchema_enumTypes(t *testing.T) {
+	schemaMode := &jsonschema.Schema{
+		Type: jsonschema.PrimitiveTypeList{jsonschema.StringType},
+		Enum: jsonschema.EnumList{"x", "y"},
+	}
+	schemaRoot := &jsonschema.Schema{
+		Title:      strptr("root"),
+		Type:       jsonschema.PrimitiveTypeList{jsonschema.ObjectType},
+		Properties: &map[string]*jsonschema.Schema{"mode": schemaMode},
+	}
+	for _, enumTypes := range []bool{false, true} {
+		locations, err := parseSchema(schemaRoot, enumTypes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := locations[schemaMode]; ok != enumTypes {
+			t.Errorf("enumTypes %v: got enum schema recorded %v, want %v", enumTypes, ok, enumTypes)
+		}
+	}
+}
@@ -1,74 +1,156 @@
 package compiler
 
 import (
-	"fmt"
 	"net/url"
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
-func resolveReferences(locationsByRoot schemaLocationsByRoot) (resolutions map[*jsonschema.Schema]*jsonschema.Schema, err error) {
+// resolver resolves $refs in root schemas, loading referenced documents as needed.
+type resolver struct {
+	loader    jsonschema.Loader
+	baseURIs  map[*jsonschema.Schema]*url.URL // the retrieval URI of each root schema (if known)
+	enumTypes bool                            // see Options.EnumTypes
+
+	docs   map[string]*jsonschema.Schema // documents loaded by loader, keyed by URI
+	loaded []*jsonschema.Schema          // documents loaded by loader that have not yet been parsed
+}
+
+func newResolver(opts Options) *resolver {
+	r := &resolver{
+		baseURIs:  map[*jsonschema.Schema]*url.URL{},
+		docs:      map[string]*jsonschema.Schema{},
+		enumTypes: opts.EnumTypes,
+	}
+	for schema, uri := range opts.BaseURIs {
+		r.baseURIs[schema] = uri
+	}
+	if opts.Loader != nil {
+		r.loader = jsonschema.LoaderFunc(func(uri *url.URL) (*jsonschema.Schema, error) {
+			return r.load(opts.Loader, uri)
+		})
+	}
+	return r
+}
+
+// load loads the document using loader, unless it was already loaded.
+func (r *resolver) load(loader jsonschema.Loader, uri *url.URL) (*jsonschema.Schema, error) {
+	if doc, ok := r.docs[uri.String()]; ok {
+		return doc, nil
+	}
+	doc, err := loader.Load(uri)
+	if err != nil {
+		return nil, err
+	}
+	r.docs[uri.String()] = doc
+	r.baseURIs[doc] = uri
+	r.loaded = append(r.loaded, doc)
+	return doc, nil
+}
+
+// resolveReferences resolves the $refs in all of the root schemas. Documents that are loaded to
+// resolve $refs are parsed and added to locationsByRoot (and their $refs are also resolved).
+func (r *resolver) resolveReferences(locationsByRoot schemaLocationsByRoot) (resolutions map[*jsonschema.Schema]*jsonschema.Schema, err error) {
 	resolutions = map[*jsonschema.Schema]*jsonschema.Schema{}
-	for root, locations := range locationsByRoot {
-		for schema, location := range locations {
-			if schema.Reference != nil {
-				ref, err := url.Parse(*schema.Reference)
-				if err != nil {
-					return nil, errors.WithMessage(err, "failed to parse $ref")
-				}
-
-				if location.id != nil {
-					// Dereference the $ref against the current base URI
-					// (https://tools.ietf.org/html/draft-handrews-json-schema-01#section-8.3.2).
-					ref = location.id.URI().ResolveReference(ref)
-				}
-
-				// If the $ref's dereferenced URI consists of only a fragment (i.e., it points to a
-				// value in the same root schema document), we must not try to resolve it in other
-				// root schemas.
-				//
-				// TODO(sqs): Check that this is correct.
-				var onlyInRoot *jsonschema.Schema
-				if *ref == (url.URL{Fragment: ref.Fragment}) {
-					onlyInRoot = root
-				}
-
-				target := resolveReference(ref, locationsByRoot, onlyInRoot)
-				if target != nil {
-					resolutions[schema] = target
-				} else {
-					return nil, fmt.Errorf("failed to resolve $ref: %q (dereferenced to %q)", *schema.Reference, ref)
-				}
+	resolved := map[*jsonschema.Schema]bool{}
+	for {
+		var roots []*jsonschema.Schema
+		for root := range locationsByRoot {
+			if !resolved[root] {
+				roots = append(roots, root)
+			}
+		}
+		if len(roots) == 0 {
+			break
+		}
+
+		for _, root := range roots {
+			resolved[root] = true
+			if err := r.resolveRootReferences(root, locationsByRoot, resolutions); err != nil {
+				return nil, err
 			}
 		}
+
+		for _, doc := range r.loaded {
+			locationsByRoot[doc], err = parseSchema(doc, r.enumTypes)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to parse loaded schema "+r.baseURIs[doc].String())
+			}
+		}
+		r.loaded = nil
 	}
 	return resolutions, nil
 }
 
-func resolveReference(ref *url.URL, locationsByRoot schemaLocationsByRoot, onlyInRoot *jsonschema.Schema) *jsonschema.Schema {
-	if isRefToMetaSchema(ref) {
-		return metaSchemaSentinel
+func (r *resolver) resolveRootReferences(root *jsonschema.Schema, locationsByRoot schemaLocationsByRoot, resolutions map[*jsonschema.Schema]*jsonschema.Schema) error {
+	registry, err := r.newRegistry(root, locationsByRoot)
+	if err != nil {
+		return err
 	}
 
-	refStr := ref.String()
-	for root, locations := range locationsByRoot {
-		if onlyInRoot != nil && root != onlyInRoot {
+	for schema := range locationsByRoot[root] {
+		if schema.Reference == nil {
 			continue
 		}
-		for schema, location := range locations {
-			if location.id != nil && location.id.String() == refStr {
-				return schema
-			}
-			// TODO(sqs): Eliminate the string hackiness here.
-			if onlyInRoot != nil && "/"+jsonschema.EncodeReferenceTokens(location.rel) == ref.Fragment {
-				return schema
-			}
+
+		base, _ := registry.ID(schema)
+		ref, err := url.Parse(*schema.Reference)
+		if err != nil {
+			return jsonschema.ErrorAt(schema.KeywordPos["$ref"], errors.WithMessage(err, "failed to parse $ref"))
+		}
+		if baseURI := base.URI(); baseURI != nil {
+			// Dereference the $ref against the current base URI
+			// (https://tools.ietf.org/html/draft-handrews-json-schema-01#section-8.3.2).
+			ref = baseURI.ResolveReference(ref)
+		}
+		if isRefToMetaSchema(ref) {
+			resolutions[schema] = metaSchemaSentinel
+			continue
 		}
+
+		target, err := registry.Resolve(base, *schema.Reference)
+		if err != nil {
+			return jsonschema.ErrorAt(schema.KeywordPos["$ref"], err)
+		}
+		resolutions[schema] = target
 	}
 	return nil
 }
 
+// newRegistry returns a registry for resolving the $refs in root. It contains root and all other
+// root schemas that have a URI (from their "$id" or the URI they were retrieved from), so that
+// they can be referred to by their URI.
+//
+// Other root schemas without a URI are omitted because they have no base URI, so fragment-only
+// $refs in root (such as "#/definitions/a") would be ambiguous.
+func (r *resolver) newRegistry(root *jsonschema.Schema, locationsByRoot schemaLocationsByRoot) (*jsonschema.Registry, error) {
+	registry := jsonschema.NewRegistry(r.loader)
+	for other := range locationsByRoot {
+		if other == root {
+			continue
+		}
+		uri := r.baseURIs[other]
+		if uri == nil && other.ID != nil {
+			var err error
+			uri, err = url.Parse(*other.ID)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to parse $id")
+			}
+		}
+		if uri == nil {
+			continue
+		}
+		if err := registry.Add(other, uri); err != nil {
+			return nil, err
+		}
+	}
+	if err := registry.Add(root, r.baseURIs[root]); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
 // metaSchemaSentinel is a sentinel value that refers to the JSON Schema describing JSON Schema
 // documents itself (the meta-schema). During the compiler's resolution phase, it is stored as the
 // resolution for $refs to the meta-schema. During the compiler's codegen phase, it is represented
@@ -76,5 +158,11 @@ func resolveReference(ref *url.URL, locationsByRoot schemaLocationsByRoot, onlyI
 var metaSchemaSentinel = &jsonschema.Schema{}
 
 func isRefToMetaSchema(ref *url.URL) bool {
-	return (ref.Scheme == "http" || ref.Scheme == "https") && ref.Host == "json-schema.org" && ref.Path == "/draft-07/schema" && (ref.Fragment == "" || ref.Fragment == "/")
+	if ref.Fragment != "" && ref.Fragment != "/" {
+		return false
+	}
+	tmp := *ref
+	tmp.Fragment = ""
+	_, ok := jsonschema.DraftForMetaSchemaURI(tmp.String())
+	return ok
 }
@@ -0,0 +1,14 @@
+package p
+
+import "github.com/sourcegraph/go-jsonschema/jsonschema"
+
+type Defs202012 struct {
+	Pair   []interface{}      `json:"pair,omitempty"`
+	Schema *jsonschema.Schema `json:"schema,omitempty"`
+	User   *User              `json:"user,omitempty"`
+	Users  []*User            `json:"users,omitempty"`
+}
+type User struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+}
@@ -0,0 +1,10 @@
+package p
+
+type Item struct {
+	Price float64 `json:"price,omitempty"`
+}
+type Legacy struct {
+	Count int    `json:"count,omitempty"`
+	Id    string `json:"id"`
+	Item  *Item  `json:"item,omitempty"`
+}
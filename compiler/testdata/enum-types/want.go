@@ -0,0 +1,121 @@
+package p
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GitURLType description: The type of Git URL to use.
+type GitURLType string
+
+const (
+	GitURLTypeHttp GitURLType = "http"
+	GitURLTypeSsh  GitURLType = "ssh"
+)
+
+func (v GitURLType) IsValid() bool {
+	switch v {
+	case GitURLTypeHttp, GitURLTypeSsh:
+		return true
+	}
+	return false
+}
+func (v *GitURLType) UnmarshalJSON(data []byte) error {
+	var x string
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	if !GitURLType(x).IsValid() {
+		return fmt.Errorf("invalid GitURLType value %q", x)
+	}
+	*v = GitURLType(x)
+	return nil
+}
+
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguageC          Language = "c++"
+	LanguageObjectiveC Language = "objective-c"
+)
+
+func (v Language) IsValid() bool {
+	switch v {
+	case LanguageGo, LanguageC, LanguageObjectiveC:
+		return true
+	}
+	return false
+}
+func (v *Language) UnmarshalJSON(data []byte) error {
+	var x string
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	if !Language(x).IsValid() {
+		return fmt.Errorf("invalid Language value %q", x)
+	}
+	*v = Language(x)
+	return nil
+}
+
+type Priority int
+
+const (
+	Priority_1 Priority = 1
+	Priority_2 Priority = 2
+	Priority_3 Priority = 3
+)
+
+func (v Priority) IsValid() bool {
+	switch v {
+	case Priority_1, Priority_2, Priority_3:
+		return true
+	}
+	return false
+}
+func (v *Priority) UnmarshalJSON(data []byte) error {
+	var x int
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	if !Priority(x).IsValid() {
+		return fmt.Errorf("invalid Priority value %d", x)
+	}
+	*v = Priority(x)
+	return nil
+}
+
+type Repository struct {
+	GitURLType GitURLType `json:"gitURLType"`
+	Kind       string     `json:"kind,omitempty"`
+	Languages  []Language `json:"languages,omitempty"`
+	Priority   Priority   `json:"priority,omitempty"`
+	Visibility Visibility `json:"visibility,omitempty"`
+}
+type Visibility string
+
+const (
+	VisibilityPublic  Visibility = "public"
+	VisibilityPrivate Visibility = "private"
+	Visibility_2      Visibility = ""
+)
+
+func (v Visibility) IsValid() bool {
+	switch v {
+	case VisibilityPublic, VisibilityPrivate, Visibility_2:
+		return true
+	}
+	return false
+}
+func (v *Visibility) UnmarshalJSON(data []byte) error {
+	var x string
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	if !Visibility(x).IsValid() {
+		return fmt.Errorf("invalid Visibility value %q", x)
+	}
+	*v = Visibility(x)
+	return nil
+}
@@ -0,0 +1,13 @@
+package p
+
+type ExternalRefs struct {
+	Groups []*Group `json:"groups,omitempty"`
+	User   User     `json:"user"`
+}
+type Group struct {
+	Id int `json:"id,omitempty"`
+}
+type User struct {
+	Group *Group `json:"group,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
@@ -0,0 +1,18 @@
+package p
+
+import (
+	"github.com/sourcegraph/go-jsonschema/formats"
+	"net"
+	"time"
+)
+
+type Event struct {
+	ClientIP  net.IP            `json:"clientIP,omitempty"`
+	Email     string            `json:"email,omitempty"`
+	EndTime   *time.Time        `json:"endTime,omitempty"`
+	Id        formats.UUID      `json:"id"`
+	ServerIPs []net.IP          `json:"serverIPs,omitempty"`
+	Source    *formats.URI      `json:"source,omitempty"`
+	Time      time.Time         `json:"time"`
+	Timeout   *formats.Duration `json:"timeout,omitempty"`
+}
@@ -0,0 +1,8 @@
+package p
+
+type Foo struct {
+	A string `json:"a,omitempty"`
+}
+type NotNested struct {
+	Foo *Foo `json:"foo,omitempty"`
+}
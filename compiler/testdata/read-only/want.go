@@ -0,0 +1,8 @@
+package p
+
+type ReadOnly struct {
+	Data      []byte `json:"data"`
+	Id        string `json:"id"`                  // read-only
+	Password  string `json:"password,omitempty"`  // write-only
+	Thumbnail []byte `json:"thumbnail,omitempty"` // read-only
+}
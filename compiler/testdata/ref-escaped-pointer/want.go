@@ -0,0 +1,12 @@
+package p
+
+type RefEscapedPointer struct {
+	Repos *RepoSettings `json:"repos,omitempty"`
+	Users *UserSettings `json:"users,omitempty"`
+}
+type RepoSettings struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+type UserSettings struct {
+	Name string `json:"name,omitempty"`
+}
@@ -0,0 +1,146 @@
+package p
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type Customer struct {
+	Name string `json:"name,omitempty"`
+}
+
+func (v *Customer) Validate() error {
+	return v.validateAt("")
+}
+func (v *Customer) validateAt(path string) error {
+	return nil
+}
+
+type Item struct {
+	Quantity int    `json:"quantity"`
+	Sku      string `json:"sku"`
+	Version  string `json:"version,omitempty"`
+}
+
+func (v *Item) Validate() error {
+	return v.validateAt("")
+}
+func (v *Item) validateAt(path string) error {
+	if v.Quantity%2 != 0 {
+		return fmt.Errorf("#%s: %v is not a multiple of 2", path+"/quantity", v.Quantity)
+	}
+	if utf8.RuneCountInString(v.Sku) < 1 {
+		return fmt.Errorf("#%s: string is shorter than the minimum length 1", path+"/sku")
+	}
+	if v.Version != "" {
+		if v.Version != "v1" {
+			return fmt.Errorf("#%s: value is not equal to the const value", path+"/version")
+		}
+	}
+	return nil
+}
+
+type Order struct {
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Budget     int               `json:"budget,omitempty"`
+	Customer   *Customer         `json:"customer,omitempty"`
+	Discount   float64           `json:"discount,omitempty"`
+	Id         string            `json:"id"`
+	Items      []*Item           `json:"items"`
+	Note       *string           `json:"note,omitempty"`
+	Priority   int               `json:"priority,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+}
+
+func (v *Order) Validate() error {
+	return v.validateAt("")
+}
+func (v *Order) validateAt(path string) error {
+	if v.Attributes != nil {
+		if len(v.Attributes) > 2 {
+			return fmt.Errorf("#%s: object has more than the maximum 2 properties", path+"/attributes")
+		}
+		for k1, e1 := range v.Attributes {
+			if utf8.RuneCountInString(e1) > 3 {
+				return fmt.Errorf("#%s: string is longer than the maximum length 3", path+"/attributes/"+strings.Replace(strings.Replace(k1, "~", "~0", -1), "/", "~1", -1))
+			}
+		}
+	}
+	if v.Budget != 0 {
+		if v.Budget%1000000 != 0 {
+			return fmt.Errorf("#%s: %v is not a multiple of 1000000", path+"/budget", v.Budget)
+		}
+	}
+	if v.Customer != nil {
+		if err := v.Customer.validateAt(path + "/customer"); err != nil {
+			return err
+		}
+	}
+	if v.Discount != 0 {
+		if r, ok := new(big.Rat).SetString(strconv.FormatFloat(v.Discount, 'g', -1, 64)); !ok || !r.Quo(r, multipleOfOrder0).IsInt() {
+			return fmt.Errorf("#%s: %v is not a multiple of 0.1", path+"/discount", v.Discount)
+		}
+		if v.Discount >= 1 {
+			return fmt.Errorf("#%s: %v is not less than the exclusive maximum 1", path+"/discount", v.Discount)
+		}
+		if v.Discount <= 0 {
+			return fmt.Errorf("#%s: %v is not greater than the exclusive minimum 0", path+"/discount", v.Discount)
+		}
+	}
+	if !patternOrder0.MatchString(v.Id) {
+		return fmt.Errorf("#%s: string does not match the pattern \"^[a-z]+-[0-9]+$\"", path+"/id")
+	}
+	if len(v.Items) < 1 {
+		return fmt.Errorf("#%s: array has fewer than the minimum 1 items", path+"/items")
+	}
+	for i2, e2 := range v.Items {
+		if e2 != nil {
+			if err := e2.validateAt(path + "/items/" + strconv.Itoa(i2)); err != nil {
+				return err
+			}
+		}
+	}
+	if v.Note != nil {
+		if utf8.RuneCountInString(*v.Note) > 10 {
+			return fmt.Errorf("#%s: string is longer than the maximum length 10", path+"/note")
+		}
+	}
+	if v.Priority != 0 {
+		if v.Priority > 5 {
+			return fmt.Errorf("#%s: %v is greater than the maximum 5", path+"/priority", v.Priority)
+		}
+		if v.Priority < 1 {
+			return fmt.Errorf("#%s: %v is less than the minimum 1", path+"/priority", v.Priority)
+		}
+	}
+	if v.Status != "" {
+		if v.Status != "pending" && v.Status != "shipped" {
+			return fmt.Errorf("#%s: value is not one of the allowed enum values", path+"/status")
+		}
+	}
+	if v.Tags != nil {
+		seen3 := make(map[string]int, len(v.Tags))
+		for i3, e3 := range v.Tags {
+			if j3, ok := seen3[e3]; ok {
+				return fmt.Errorf("#%s: array items %d and %d are equal, but items must be unique", path+"/tags", j3, i3)
+			}
+			seen3[e3] = i3
+		}
+		for i4, e4 := range v.Tags {
+			if utf8.RuneCountInString(e4) < 1 {
+				return fmt.Errorf("#%s: string is shorter than the minimum length 1", path+"/tags/"+strconv.Itoa(i4))
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	patternOrder0       = regexp.MustCompile("^[a-z]+-[0-9]+$")
+	multipleOfOrder0, _ = new(big.Rat).SetString("0.1")
+)
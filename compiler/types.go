@@ -2,6 +2,7 @@ package compiler
 
 import (
 	"go/ast"
+	"strings"
 
 	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
@@ -23,6 +24,14 @@ func goBuiltinType(typ jsonschema.PrimitiveType) string {
 	}
 }
 
+// isEmittedAsGoStructType reports whether a Go struct type is declared for the schema.
+func isEmittedAsGoStructType(schema *jsonschema.Schema) bool {
+	if schema.Go != nil && schema.Go.TaggedUnionType {
+		return true
+	}
+	return len(schema.Type) == 1 && schema.Type[0] == jsonschema.ObjectType && schema.Properties != nil
+}
+
 func isEmittedAsGoNamedType(schema *jsonschema.Schema) bool {
 	return len(schema.Type) == 1 && schema.Type[0] == jsonschema.ObjectType
 }
@@ -44,3 +53,11 @@ func isBasicType(x ast.Expr) bool {
 }
 
 func forceGoPointer(schema *jsonschema.Schema) bool { return schema.Go != nil && schema.Go.Pointer }
+
+func isReadOnly(schema *jsonschema.Schema) bool { return schema.ReadOnly != nil && *schema.ReadOnly }
+
+// isBase64Encoded reports whether the schema's "contentEncoding" is base64 (RFC 4648, which is the
+// encoding that encoding/json uses for []byte values).
+func isBase64Encoded(schema *jsonschema.Schema) bool {
+	return schema.ContentEncoding != nil && strings.EqualFold(*schema.ContentEncoding, "base64")
+}
@@ -0,0 +1,95 @@
+package compiler
+
+import (
+	"encoding/json"
+	"testing"
+
+	testdata_validate "github.com/sourcegraph/go-jsonschema/compiler/testdata/validate"
+)
+
+// TestValidate depends on the generated ./testdata/validate/want.go file, which you can overwrite
+// with the latest generated code by running `go test -test.write-want`.
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		wantErr string
+	}{
+		"valid": {
+			data: `{"id":"a-1","status":"shipped","note":"n","priority":5,"discount":0.5,"items":[{"sku":"s","quantity":2,"version":"v1"}],"tags":["x","y"],"customer":{"name":"c"},"attributes":{"a":"b"}}`,
+		},
+		"pattern": {
+			data:    `{"id":"A1","items":[{"sku":"s","quantity":2}]}`,
+			wantErr: `#/id: string does not match the pattern "^[a-z]+-[0-9]+$"`,
+		},
+		"enum": {
+			data:    `{"id":"a-1","status":"lost","items":[{"sku":"s","quantity":2}]}`,
+			wantErr: "#/status: value is not one of the allowed enum values",
+		},
+		"pointer": {
+			data:    `{"id":"a-1","note":"this is too long","items":[{"sku":"s","quantity":2}]}`,
+			wantErr: "#/note: string is longer than the maximum length 10",
+		},
+		"maximum": {
+			data:    `{"id":"a-1","priority":6,"items":[{"sku":"s","quantity":2}]}`,
+			wantErr: "#/priority: 6 is greater than the maximum 5",
+		},
+		"exclusiveMaximum": {
+			data:    `{"id":"a-1","discount":1,"items":[{"sku":"s","quantity":2}]}`,
+			wantErr: "#/discount: 1 is not less than the exclusive maximum 1",
+		},
+		"minItems": {
+			data:    `{"id":"a-1","items":[]}`,
+			wantErr: "#/items: array has fewer than the minimum 1 items",
+		},
+		"item": {
+			data:    `{"id":"a-1","items":[{"sku":"s","quantity":2},{"sku":"","quantity":2}]}`,
+			wantErr: "#/items/1/sku: string is shorter than the minimum length 1",
+		},
+		"multipleOf": {
+			data:    `{"id":"a-1","items":[{"sku":"s","quantity":3}]}`,
+			wantErr: "#/items/0/quantity: 3 is not a multiple of 2",
+		},
+		"decimal multipleOf": {
+			data: `{"id":"a-1","discount":0.3,"items":[{"sku":"s","quantity":2}]}`,
+		},
+		"not decimal multipleOf": {
+			data:    `{"id":"a-1","discount":0.35,"items":[{"sku":"s","quantity":2}]}`,
+			wantErr: "#/discount: 0.35 is not a multiple of 0.1",
+		},
+		"large integer multipleOf": {
+			data:    `{"id":"a-1","budget":1500000,"items":[{"sku":"s","quantity":2}]}`,
+			wantErr: "#/budget: 1500000 is not a multiple of 1000000",
+		},
+		"const": {
+			data:    `{"id":"a-1","items":[{"sku":"s","quantity":2,"version":"v2"}]}`,
+			wantErr: "#/items/0/version: value is not equal to the const value",
+		},
+		"uniqueItems": {
+			data:    `{"id":"a-1","items":[{"sku":"s","quantity":2}],"tags":["x","y","x"]}`,
+			wantErr: "#/tags: array items 0 and 2 are equal, but items must be unique",
+		},
+		"maxProperties": {
+			data:    `{"id":"a-1","items":[{"sku":"s","quantity":2}],"attributes":{"a":"1","b":"2","c":"3"}}`,
+			wantErr: "#/attributes: object has more than the maximum 2 properties",
+		},
+		"additionalProperties": {
+			data:    `{"id":"a-1","items":[{"sku":"s","quantity":2}],"attributes":{"a/b":"long"}}`,
+			wantErr: "#/attributes/a~1b: string is longer than the maximum length 3",
+		},
+	}
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			var v testdata_validate.Order
+			if err := json.Unmarshal([]byte(test.data), &v); err != nil {
+				t.Fatal(err)
+			}
+			var gotErr string
+			if err := v.Validate(); err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != test.wantErr {
+				t.Errorf("got error %q, want %q", gotErr, test.wantErr)
+			}
+		})
+	}
+}
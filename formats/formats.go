@@ -0,0 +1,223 @@
+// Package formats provides Go types for the values of JSON Schema string formats that have no
+// suitable type in the standard library. The compiler uses these types for string schemas with a
+// "format" (see compiler.DefaultFormatTypes).
+//
+// Each type is encoded in JSON as a string (by implementing encoding.TextMarshaler and
+// encoding.TextUnmarshaler).
+package formats
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// URI holds an absolute URI (the value of a string with "format": "uri").
+type URI struct {
+	url.URL
+}
+
+// ParseURI parses an absolute URI.
+func ParseURI(s string) (*URI, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsAbs() {
+		return nil, fmt.Errorf("URI %q is not absolute", s)
+	}
+	return &URI{URL: *u}, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u URI) MarshalText() ([]byte, error) {
+	return []byte(u.URL.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *URI) UnmarshalText(text []byte) error {
+	parsed, err := ParseURI(string(text))
+	if err != nil {
+		return err
+	}
+	*u = *parsed
+	return nil
+}
+
+// UUID holds a UUID (the value of a string with "format": "uuid"), as defined in [RFC
+// 4122](https://tools.ietf.org/html/rfc4122).
+type UUID [16]byte
+
+// ParseUUID parses a UUID in its canonical form (such as
+// "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"). Hex digits may be uppercase or lowercase.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+// String returns the UUID in its canonical form (with lowercase hex digits).
+func (u UUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Duration holds a duration (the value of a string with "format": "duration"), which is an ISO
+// 8601 duration (such as "PT1H30M").
+type Duration struct {
+	time.Duration
+}
+
+// ParseDuration parses an ISO 8601 duration in weeks (such as "P2W") or in days, hours, minutes
+// and seconds (such as "P1DT12H" or "PT1.5S"). A day is 24 hours. Years and months are not
+// supported, because their lengths vary. Only the seconds may have a fraction. It returns an error
+// if the duration is too long to be represented as a time.Duration (about 292 years).
+func ParseDuration(s string) (time.Duration, error) {
+	invalid := func() (time.Duration, error) { return 0, fmt.Errorf("invalid duration %q", s) }
+	overflow := func() (time.Duration, error) { return 0, fmt.Errorf("duration %q is out of range", s) }
+	var d time.Duration
+	// add adds n units to d, and reports whether the sum is representable.
+	add := func(n int64, unit time.Duration) bool {
+		if n > int64((math.MaxInt64-d)/unit) {
+			return false
+		}
+		d += time.Duration(n) * unit
+		return true
+	}
+	if !strings.HasPrefix(s, "P") || len(s) == 1 {
+		return invalid()
+	}
+	rest := s[1:]
+	if strings.HasSuffix(rest, "W") {
+		n, err := strconv.ParseInt(rest[:len(rest)-1], 10, 64)
+		if err != nil || n < 0 {
+			return invalid()
+		}
+		if !add(n, 7*24*time.Hour) {
+			return overflow()
+		}
+		return d, nil
+	}
+
+	date, clock := rest, ""
+	if i := strings.IndexByte(rest, 'T'); i != -1 {
+		date, clock = rest[:i], rest[i+1:]
+		if clock == "" {
+			return invalid()
+		}
+	}
+	if date != "" {
+		if !strings.HasSuffix(date, "D") {
+			return invalid()
+		}
+		n, err := strconv.ParseInt(date[:len(date)-1], 10, 64)
+		if err != nil || n < 0 {
+			return invalid()
+		}
+		if !add(n, 24*time.Hour) {
+			return overflow()
+		}
+	}
+	// The units of the time components, in the order in which they must appear.
+	units := []struct {
+		designator byte
+		unit       time.Duration
+	}{{'H', time.Hour}, {'M', time.Minute}, {'S', time.Second}}
+	for clock != "" {
+		i := strings.IndexAny(clock, "HMS")
+		if i <= 0 {
+			return invalid()
+		}
+		for len(units) > 0 && units[0].designator != clock[i] {
+			units = units[1:]
+		}
+		if len(units) == 0 {
+			return invalid() // out of order or repeated
+		}
+		if units[0].unit == time.Second {
+			f, err := strconv.ParseFloat(clock[:i], 64)
+			if err != nil || f < 0 || strings.ContainsAny(clock[:i], "eE+-") {
+				return invalid()
+			}
+			ns := math.Round(f * float64(time.Second))
+			if ns >= float64(math.MaxInt64-d) { // float64(math.MaxInt64) rounds up to 2^63
+				return overflow()
+			}
+			d += time.Duration(ns)
+		} else {
+			n, err := strconv.ParseInt(clock[:i], 10, 64)
+			if err != nil || n < 0 {
+				return invalid()
+			}
+			if !add(n, units[0].unit) {
+				return overflow()
+			}
+		}
+		clock, units = clock[i+1:], units[1:]
+	}
+	return d, nil
+}
+
+// FormatDuration returns the ISO 8601 representation of the duration in hours, minutes and
+// seconds (such as "PT1H30M"). The duration must not be negative.
+func FormatDuration(d time.Duration) (string, error) {
+	if d < 0 {
+		return "", errors.New("negative durations can't be represented in ISO 8601")
+	}
+	s := "PT"
+	if h := d / time.Hour; h > 0 {
+		s += strconv.FormatInt(int64(h), 10) + "H"
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		s += strconv.FormatInt(int64(m), 10) + "M"
+		d -= m * time.Minute
+	}
+	if d > 0 || s == "PT" {
+		s += strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+	}
+	return s, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	s, err := FormatDuration(d.Duration)
+	return []byte(s), err
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
@@ -0,0 +1,93 @@
+package formats
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURI(t *testing.T) {
+	var u URI
+	if err := json.Unmarshal([]byte(`"https://example.com/a?b#c"`), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "example.com" || u.Path != "/a" {
+		t.Errorf("got %+v", u.URL)
+	}
+	if data, err := json.Marshal(u); err != nil || string(data) != `"https://example.com/a?b#c"` {
+		t.Errorf("got %s (error %v)", data, err)
+	}
+	if err := json.Unmarshal([]byte(`"/relative"`), &u); err == nil {
+		t.Error("got no error for relative URI")
+	}
+}
+
+func TestUUID(t *testing.T) {
+	var u UUID
+	if err := json.Unmarshal([]byte(`"F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6"`), &u); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := json.Marshal(u); err != nil || string(data) != `"f81d4fae-7dec-11d0-a765-00a0c91e6bf6"` {
+		t.Errorf("got %s (error %v)", data, err)
+	}
+	for _, s := range []string{"", "f81d4fae7dec11d0a76500a0c91e6bf6", "f81d4fae-7dec-11d0-a765-00a0c91e6bfg", "f81d4fae-7dec-11d0-a765_00a0c91e6bf6"} {
+		if _, err := ParseUUID(s); err == nil {
+			t.Errorf("%q: got no error", s)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"P2W":         2 * 7 * 24 * time.Hour,
+		"P1D":         24 * time.Hour,
+		"P1DT12H":     36 * time.Hour,
+		"PT1H30M":     90 * time.Minute,
+		"PT90M":       90 * time.Minute,
+		"PT1.5S":      1500 * time.Millisecond,
+		"PT0S":        0,
+		"PT1H2M3.25S": time.Hour + 2*time.Minute + 3250*time.Millisecond,
+		"P106751D":    106751 * 24 * time.Hour,
+	}
+	for s, want := range tests {
+		got, err := ParseDuration(s)
+		if err != nil {
+			t.Errorf("%q: %s", s, err)
+		} else if got != want {
+			t.Errorf("%q: got %s, want %s", s, got, want)
+		}
+	}
+
+	for _, s := range []string{"", "P", "PT", "P1DT", "P1Y", "P1M", "PT1S1M", "PT1H1H", "PT-1S", "PT1.5M", "1H", "P1WT1H"} {
+		if _, err := ParseDuration(s); err == nil {
+			t.Errorf("%q: got no error", s)
+		}
+	}
+
+	for _, s := range []string{"P106752D", "P15251W", "PT9999999999H", "P106751DT24H", "PT2562047H48M", "PT9223372037S", "PT9223372036.854775807S"} {
+		if _, err := ParseDuration(s); err == nil || !strings.Contains(err.Error(), "out of range") {
+			t.Errorf("%q: got error %v, want out of range", s, err)
+		}
+	}
+
+	for d, want := range map[time.Duration]string{
+		0:                          "PT0S",
+		36 * time.Hour:             "PT36H",
+		90 * time.Minute:           "PT1H30M",
+		1500 * time.Millisecond:    "PT1.5S",
+		time.Minute + time.Second:  "PT1M1S",
+		time.Hour + 10*time.Second: "PT1H10S",
+	} {
+		data, err := json.Marshal(Duration{d})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `"`+want+`"` {
+			t.Errorf("%s: got %s, want %q", d, data, want)
+		}
+	}
+	if _, err := json.Marshal(Duration{-time.Second}); err == nil {
+		t.Error("got no error for negative duration")
+	}
+}
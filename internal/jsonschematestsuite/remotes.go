@@ -0,0 +1,47 @@
+package jsonschematestsuite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// RemotesBaseURL is the base URL that the test suite's remote schemas (in its remotes/ directory)
+// are expected to be served from. Test cases in refRemote.json refer to schemas at this URL.
+const RemotesBaseURL = "http://localhost:1234/"
+
+// RemotesDir returns the directory that contains the test suite's remote schemas.
+func RemotesDir(internalDir string) string {
+	return filepath.Join(internalDir, "jsonschematestsuite", "testdata", "official", "remotes")
+}
+
+// RemotesLoader returns a loader that reads the test suite's remote schemas from disk instead of
+// fetching them from RemotesBaseURL.
+func RemotesLoader(internalDir string) jsonschema.Loader {
+	return jsonschema.FSLoader{FS: os.DirFS(RemotesDir(internalDir)), BaseURI: mustParseURL(RemotesBaseURL)}
+}
+
+// NewRemotesServer starts and returns an HTTP server that serves the test suite's remote schemas.
+// The caller must call Close on the server when finished. Use RemotesServerLoader to fetch remote
+// schemas from the server.
+func NewRemotesServer(internalDir string) *httptest.Server {
+	return httptest.NewServer(http.FileServer(http.Dir(RemotesDir(internalDir))))
+}
+
+// RemotesServerLoader returns a loader that fetches the test suite's remote schemas from the
+// server (created by NewRemotesServer) instead of from RemotesBaseURL.
+func RemotesServerLoader(server *httptest.Server) jsonschema.Loader {
+	return jsonschema.HTTPLoader{Client: server.Client(), Server: mustParseURL(server.URL)}
+}
+
+func mustParseURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
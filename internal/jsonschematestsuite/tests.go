@@ -2,6 +2,7 @@ package jsonschematestsuite
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,8 +13,9 @@ import (
 
 // File is a file of test groups from the JSON Schema test suite.
 type File struct {
-	Name string `json:"-"`
-	path string
+	Name  string `json:"-"`
+	path  string
+	draft jsonschema.Draft // the draft of the schemas (if earlier than draft-07, they are upgraded when read)
 
 	Groups []*Group // call Read to populate this field
 }
@@ -29,7 +31,13 @@ func (f *File) Read() error {
 		return err
 	}
 	for _, g := range f.Groups {
-		if err := json.Unmarshal(g.RawSchema, &g.Schema); err != nil {
+		data := []byte(g.RawSchema)
+		if f.draft != 0 && f.draft < jsonschema.Draft7 {
+			if data, err = jsonschema.Upgrade(data, f.draft); err != nil {
+				return err
+			}
+		}
+		if err := json.Unmarshal(data, &g.Schema); err != nil {
 			return err
 		}
 	}
@@ -60,30 +68,72 @@ type TestCase struct {
 	Valid       bool
 }
 
-// Files returns all test files from the JSON Schema official test suite and this library's own test
-// suite.
+// Files returns all draft-07 test files from the JSON Schema official test suite and this library's
+// own test suite.
 func Files(internalDir string) (files []File, err error) {
-	officialTestSuiteDir := filepath.Join(internalDir, "jsonschematestsuite", "testdata", "official")
 	for _, root := range []string{
 		filepath.Join(internalDir, "jsonschematestsuite", "testdata"),
-		filepath.Join(officialTestSuiteDir, "tests", "draft7"),
+		filepath.Join(officialDir(internalDir), "tests", "draft7"),
 	} {
-		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-			if path == officialTestSuiteDir {
-				return filepath.SkipDir
-			}
-			if !info.Mode().IsRegular() || filepath.Ext(info.Name()) != ".json" {
-				return nil
-			}
-			files = append(files, File{
-				Name: strings.TrimSuffix(strings.TrimPrefix(path, root+string(os.PathSeparator)), ".json"),
-				path: path,
-			})
-			return nil
-		})
+		rootFiles, err := readDir(internalDir, root)
 		if err != nil {
 			return nil, err
 		}
+		files = append(files, rootFiles...)
 	}
+	return files, nil
+}
+
+// DraftFiles returns all test files from the JSON Schema official test suite for the draft. The
+// schemas of drafts earlier than draft-07 are upgraded to draft-07 (with jsonschema.Upgrade) when
+// the files are read.
+func DraftFiles(internalDir string, draft jsonschema.Draft) ([]File, error) {
+	dir, ok := draftDirs[draft]
+	if !ok {
+		return nil, fmt.Errorf("no test suite for JSON Schema %s", draft)
+	}
+	files, err := readDir(internalDir, filepath.Join(officialDir(internalDir), "tests", dir))
+	for i := range files {
+		files[i].draft = draft
+	}
+	return files, err
+}
+
+// draftDirs maps drafts to the names of their directories in the test suite's tests/ directory.
+var draftDirs = map[jsonschema.Draft]string{
+	jsonschema.Draft4:      "draft4",
+	jsonschema.Draft6:      "draft6",
+	jsonschema.Draft7:      "draft7",
+	jsonschema.Draft201909: "draft2019-09",
+	jsonschema.Draft202012: "draft2020-12",
+}
+
+func officialDir(internalDir string) string {
+	return filepath.Join(internalDir, "jsonschematestsuite", "testdata", "official")
+}
+
+// readDir returns the test files in the directory tree rooted at root (excluding the official test
+// suite, unless root is in it).
+func readDir(internalDir, root string) (files []File, err error) {
+	officialTestSuiteDir := officialDir(internalDir)
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && strings.HasPrefix(path, officialTestSuiteDir) {
+				return fmt.Errorf("%s (run 'git submodule update --init' to fetch the official JSON Schema test suite)", err)
+			}
+			return err
+		}
+		if path == officialTestSuiteDir {
+			return filepath.SkipDir
+		}
+		if !info.Mode().IsRegular() || filepath.Ext(info.Name()) != ".json" {
+			return nil
+		}
+		files = append(files, File{
+			Name: strings.TrimSuffix(strings.TrimPrefix(path, root+string(os.PathSeparator)), ".json"),
+			path: path,
+		})
+		return nil
+	})
 	return files, err
 }
@@ -0,0 +1,212 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Bundle returns a self-contained copy of the root schema document, in which the documents that
+// are referred to by "$ref" (directly or indirectly) are placed under "definitions" and the "$ref"
+// values that refer to them are rewritten to refer to their new locations. The root schema is not
+// modified.
+//
+// The uri is the URI that the root schema was retrieved from (used to resolve relative "$ref"
+// values), or nil if unknown. The loader loads the referenced documents.
+//
+// The "$id" values in the bundled documents are removed (because they would change the base URI
+// of the rewritten "$ref" values). References to the JSON Schema meta-schemas (at
+// json-schema.org) are not bundled.
+func Bundle(root *Schema, uri *url.URL, loader Loader) (*Schema, error) {
+	root, err := copySchema(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the loaded documents (and use copies of them, because they are modified).
+	type document struct {
+		uri       *url.URL
+		schema    *Schema
+		locations map[*Schema]string // JSON Pointer of each (sub)schema in the document
+		name      string             // the property name under the root's "definitions"
+	}
+	rootDoc := &document{uri: uri, schema: root, locations: schemaPointers(root)}
+	var docs []*document
+	registry := NewRegistry(LoaderFunc(func(uri *url.URL) (*Schema, error) {
+		if loader == nil {
+			return nil, fmt.Errorf("no loader for schema %s", uri)
+		}
+		schema, err := loader.Load(uri)
+		if err != nil {
+			return nil, err
+		}
+		if schema, err = copySchema(schema); err != nil {
+			return nil, err
+		}
+		docs = append(docs, &document{uri: uri, schema: schema, locations: schemaPointers(schema)})
+		return schema, nil
+	}))
+	if err := registry.add(root, uri, nil, registry.defaultDialect); err != nil {
+		return nil, err
+	}
+
+	// Resolve all $refs (in the root document and in each loaded document) before modifying
+	// anything, because the resolution depends on the "$id" values.
+	type reference struct {
+		schema *Schema   // the schema with the $ref
+		target *Schema   // the schema that the $ref refers to
+		doc    *document // the document that contains target
+	}
+	var refs []reference
+	for i := -1; i < len(docs); i++ {
+		doc := rootDoc
+		if i >= 0 {
+			doc = docs[i]
+		}
+		for _, schema := range sortedByPointer(doc.locations) {
+			if schema.Reference == nil || isMetaSchemaReference(registry, schema) {
+				continue
+			}
+			target, err := registry.resolve(schema)
+			if err != nil {
+				return nil, err
+			}
+			ref := reference{schema: schema, target: target}
+			for _, d := range append([]*document{rootDoc}, docs...) {
+				if _, ok := d.locations[target]; ok {
+					ref.doc = d
+					break
+				}
+			}
+			if ref.doc == nil {
+				return nil, fmt.Errorf("unable to bundle $ref %q (it does not refer to a subschema)", *schema.Reference)
+			}
+			refs = append(refs, ref)
+		}
+	}
+
+	// Place the loaded documents under "definitions".
+	if len(docs) > 0 && root.Definitions == nil {
+		root.Definitions = &map[string]*Schema{}
+	}
+	for _, doc := range docs {
+		doc.name = bundleDefinitionName(doc.uri, *root.Definitions)
+		(*root.Definitions)[doc.name] = doc.schema
+		Walk(idRemover{}, doc.schema)
+	}
+
+	// Rewrite the $refs. References from the root document to itself are unchanged.
+	for _, ref := range refs {
+		if ref.doc == rootDoc {
+			if _, inRoot := rootDoc.locations[ref.schema]; inRoot {
+				continue
+			}
+		}
+		pointer := ref.doc.locations[ref.target]
+		if ref.doc != rootDoc {
+			pointer = FormatJSONPointer([]string{"definitions", ref.doc.name}) + pointer
+		}
+		s := (&url.URL{Fragment: pointer}).String()
+		if s == "" {
+			s = "#"
+		}
+		ref.schema.Reference = &s
+	}
+
+	return root, nil
+}
+
+// copySchema returns a deep copy of the schema.
+func copySchema(schema *Schema) (*Schema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var dup *Schema
+	if err := json.Unmarshal(data, &dup); err != nil {
+		return nil, errors.WithMessage(err, "failed to copy schema")
+	}
+	return dup, nil
+}
+
+// schemaPointers returns the JSON Pointer of each (sub)schema in the schema document.
+func schemaPointers(root *Schema) map[*Schema]string {
+	v := pointerVisitor{pointers: map[*Schema]string{}}
+	Walk(&v, root)
+	return v.pointers
+}
+
+// pointerVisitor implements Visitor.
+type pointerVisitor struct {
+	pointers map[*Schema]string
+	tokens   []ReferenceToken
+}
+
+// Visit implements Visitor.
+func (v *pointerVisitor) Visit(schema *Schema, rel []ReferenceToken) Visitor {
+	if schema == nil {
+		return nil
+	}
+	w := *v // copy
+	w.tokens = appendTokens(v.tokens, rel...)
+	w.pointers[schema] = encodeJSONPointer(w.tokens)
+	return &w
+}
+
+// sortedByPointer returns the schemas in the map, sorted by their JSON Pointer.
+func sortedByPointer(pointers map[*Schema]string) []*Schema {
+	schemas := make([]*Schema, 0, len(pointers))
+	for schema := range pointers {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool { return pointers[schemas[i]] < pointers[schemas[j]] })
+	return schemas
+}
+
+// isMetaSchemaReference reports whether the "$ref" of the schema refers to a JSON Schema
+// meta-schema.
+func isMetaSchemaReference(registry *Registry, schema *Schema) bool {
+	ref, err := url.Parse(*schema.Reference)
+	if err != nil {
+		return false
+	}
+	if base := registry.ids[schema].Base; base != nil {
+		ref = base.ResolveReference(ref)
+	}
+	return ref.Host == "json-schema.org"
+}
+
+// bundleDefinitionName returns the name under "definitions" for the bundled document, derived
+// from the last path element of its URI (without the extension) and not already in definitions.
+func bundleDefinitionName(uri *url.URL, definitions map[string]*Schema) string {
+	name := strings.TrimSuffix(path.Base(uri.Path), path.Ext(uri.Path))
+	if name == "" || name == "." || name == "/" {
+		name = uri.Host
+	}
+	if name == "" {
+		name = "schema"
+	}
+	unique := name
+	for i := 2; definitions[unique] != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	return unique
+}
+
+// idRemover implements Visitor by removing the "$id" of each schema.
+type idRemover struct{}
+
+// Visit implements Visitor.
+func (idRemover) Visit(schema *Schema, rel []ReferenceToken) Visitor {
+	if schema == nil {
+		return nil
+	}
+	schema.ID = nil
+	return idRemover{}
+}
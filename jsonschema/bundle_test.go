@@ -0,0 +1,92 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/internal/testutil"
+)
+
+func TestBundle(t *testing.T) {
+	loader := MapLoader{
+		"http://example.com/user.json": []byte(`{
+  "$id": "http://example.com/user.json",
+  "properties": {
+    "name": {"$ref": "defs/name.json"},
+    "group": {"$ref": "#/definitions/Group"}
+  },
+  "definitions": {"Group": {"properties": {"id": {"type": "integer"}}}}
+}`),
+		"http://example.com/defs/name.json": []byte(`{"type": "string", "minLength": 1}`),
+	}
+	var root *Schema
+	if err := json.Unmarshal([]byte(`{
+  "$id": "http://example.com/root.json",
+  "properties": {
+    "owner": {"$ref": "user.json"},
+    "groups": {"items": {"$ref": "user.json#/definitions/Group"}},
+    "local": {"$ref": "#/definitions/user"}
+  },
+  "definitions": {"user": {"type": "string"}}
+}`), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := Bundle(root, nil, loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{
+  "$id": "http://example.com/root.json",
+  "properties": {
+    "owner": {"$ref": "#/definitions/user2"},
+    "groups": {"items": {"$ref": "#/definitions/user2/definitions/Group"}},
+    "local": {"$ref": "#/definitions/user"}
+  },
+  "definitions": {
+    "user": {"type": "string"},
+    "user2": {
+      "properties": {
+        "name": {"$ref": "#/definitions/name"},
+        "group": {"$ref": "#/definitions/user2/definitions/Group"}
+      },
+      "definitions": {"Group": {"properties": {"id": {"type": "integer"}}}}
+    },
+    "name": {"type": "string", "minLength": 1}
+  }
+}`
+	if got, want := testutil.CanonicalJSON(got), testutil.CanonicalJSON([]byte(want)); string(got) != string(want) {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	// The original root schema is not modified.
+	if ref := *(*root.Properties)["owner"].Reference; ref != "user.json" {
+		t.Errorf("root schema was modified: got $ref %q", ref)
+	}
+
+	// The bundle validates instances without a loader.
+	if err := Validate(bundle, []byte(`{"owner":{"name":"","group":{"id":1}}}`)); err == nil {
+		t.Error("got valid, want invalid")
+	}
+	if err := Validate(bundle, []byte(`{"owner":{"name":"a"},"groups":[{"id":1}]}`)); err != nil {
+		t.Error(err)
+	}
+
+	t.Run("meta-schema", func(t *testing.T) {
+		var root *Schema
+		if err := json.Unmarshal([]byte(`{"$ref": "http://json-schema.org/draft-07/schema#"}`), &root); err != nil {
+			t.Fatal(err)
+		}
+		bundle, err := Bundle(root, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := *bundle.Reference, *root.Reference; got != want {
+			t.Errorf("got $ref %q, want %q", got, want)
+		}
+	})
+}
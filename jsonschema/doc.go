@@ -1,5 +1,11 @@
 // Package jsonschema reads and describes JSON Schema documents.
 //
 // Compatible with JSON Schema draft-07 as specified in
-// [draft-handrews-json-schema-01](https://tools.ietf.org/html/draft-handrews-json-schema-01).
+// [draft-handrews-json-schema-01](https://tools.ietf.org/html/draft-handrews-json-schema-01), and
+// with drafts 2019-09 and 2020-12 (including "$defs", "$anchor", "$dynamicRef", "prefixItems" and
+// the "unevaluated*" keywords). The draft of each schema is selected by its "$schema" keyword (see
+// Draft).
+//
+// Custom keywords (such as "x-"-prefixed extension keywords) can be given Go types, validation
+// semantics and subschemas by registering them (see RegisterKeyword).
 package jsonschema
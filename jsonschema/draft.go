@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"net/url"
+	"strings"
+)
+
+// A Draft is a version of the JSON Schema specification. A schema's draft determines which
+// keywords it may use and how they behave. It is selected by the schema's "$schema" keyword.
+//
+// Drafts are ordered: a later draft compares greater than an earlier draft.
+type Draft int
+
+// The supported drafts. Schemas of drafts earlier than draft-07 are upgraded to draft-07 when they
+// are read (see Upgrade).
+const (
+	Draft4      Draft = 4      // draft-04 (draft-zyp-json-schema-04)
+	Draft6      Draft = 6      // draft-06 (draft-wright-json-schema-01)
+	Draft7      Draft = 7      // draft-07 (draft-handrews-json-schema-01)
+	Draft201909 Draft = 201909 // 2019-09 (draft-handrews-json-schema-02)
+	Draft202012 Draft = 202012 // 2020-12
+)
+
+// DefaultDraft is the draft of schemas that do not specify one with "$schema".
+const DefaultDraft = Draft7
+
+var draftMetaSchemaURIs = map[Draft]string{
+	Draft4:      "http://json-schema.org/draft-04/schema#",
+	Draft6:      "http://json-schema.org/draft-06/schema#",
+	Draft7:      "http://json-schema.org/draft-07/schema#",
+	Draft201909: "https://json-schema.org/draft/2019-09/schema",
+	Draft202012: "https://json-schema.org/draft/2020-12/schema",
+}
+
+func (d Draft) String() string {
+	switch d {
+	case Draft4:
+		return "draft-04"
+	case Draft6:
+		return "draft-06"
+	case Draft7:
+		return "draft-07"
+	case Draft201909:
+		return "2019-09"
+	case Draft202012:
+		return "2020-12"
+	}
+	return "unknown draft"
+}
+
+// MetaSchemaURI returns the URI of the draft's meta-schema (which is the "$schema" value that
+// selects the draft).
+func (d Draft) MetaSchemaURI() string { return draftMetaSchemaURIs[d] }
+
+// DraftForMetaSchemaURI returns the draft whose meta-schema is identified by the URI (a "$schema"
+// value). The scheme ("http" or "https") and an empty fragment are ignored. It returns false if the
+// URI does not identify a known draft's meta-schema.
+func DraftForMetaSchemaURI(uri string) (Draft, bool) {
+	key := metaSchemaKey(uri)
+	for d, s := range draftMetaSchemaURIs {
+		if metaSchemaKey(s) == key {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func metaSchemaKey(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	if u.Scheme == "https" {
+		u.Scheme = "http"
+	}
+	return strings.TrimSuffix(u.String(), "#")
+}
+
+// Draft returns the draft that the schema's "$schema" keyword selects. It returns false if the
+// schema has no "$schema" or if it does not identify a known draft's meta-schema.
+func (s *Schema) Draft() (Draft, bool) {
+	if s.SchemaRef == nil {
+		return 0, false
+	}
+	return DraftForMetaSchemaURI(*s.SchemaRef)
+}
+
+// The vocabularies whose keywords can be disabled by a meta-schema's "$vocabulary" (in 2019-09
+// and later). See
+// https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.8.1.2.
+var validationVocabularies = map[string]bool{
+	"https://json-schema.org/draft/2019-09/vocab/validation": true,
+	"https://json-schema.org/draft/2020-12/vocab/validation": true,
+}
+
+// A dialect describes how a schema is interpreted: its draft and (for meta-schemas that declare
+// their vocabularies) which keywords are enabled.
+type dialect struct {
+	draft      Draft
+	validation bool // whether the validation vocabulary (assertions such as "type") is enabled
+}
+
+var defaultDialect = dialect{draft: DefaultDraft, validation: true}
+
+// dialectForMetaSchema returns the dialect that schemas whose "$schema" refers to the (custom)
+// meta-schema use. The meta-schema's own "$schema" determines the draft, and its "$vocabulary"
+// determines which vocabularies are enabled.
+func dialectForMetaSchema(metaSchema *Schema) dialect {
+	d := dialect{draft: DefaultDraft, validation: true}
+	if draft, ok := metaSchema.Draft(); ok {
+		d.draft = draft
+	}
+	if metaSchema.Vocabulary != nil {
+		d.validation = false
+		for vocab := range *metaSchema.Vocabulary {
+			if validationVocabularies[vocab] {
+				d.validation = true
+			}
+		}
+	}
+	return d
+}
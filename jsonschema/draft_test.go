@@ -0,0 +1,51 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDraftForMetaSchemaURI(t *testing.T) {
+	tests := map[string]struct {
+		draft Draft
+		ok    bool
+	}{
+		"http://json-schema.org/draft-04/schema#":        {draft: Draft4, ok: true},
+		"http://json-schema.org/draft-06/schema":         {draft: Draft6, ok: true},
+		"http://json-schema.org/draft-07/schema#":        {draft: Draft7, ok: true},
+		"http://json-schema.org/draft-07/schema":         {draft: Draft7, ok: true},
+		"https://json-schema.org/draft-07/schema#":       {draft: Draft7, ok: true},
+		"https://json-schema.org/draft/2019-09/schema":   {draft: Draft201909, ok: true},
+		"https://json-schema.org/draft/2020-12/schema#":  {draft: Draft202012, ok: true},
+		"https://json-schema.org/draft/2020-12/schema#a": {},
+		"https://example.com/schema":                     {},
+	}
+	for uri, want := range tests {
+		t.Run(uri, func(t *testing.T) {
+			draft, ok := DraftForMetaSchemaURI(uri)
+			if draft != want.draft || ok != want.ok {
+				t.Errorf("got %v %v, want %v %v", draft, ok, want.draft, want.ok)
+			}
+		})
+	}
+}
+
+func TestDialectForMetaSchema(t *testing.T) {
+	tests := map[string]dialect{
+		`{}`: {draft: DefaultDraft, validation: true},
+		`{"$schema": "https://json-schema.org/draft/2020-12/schema"}`:                                                                                                                                            {draft: Draft202012, validation: true},
+		`{"$schema": "https://json-schema.org/draft/2019-09/schema", "$vocabulary": {"https://json-schema.org/draft/2019-09/vocab/core": true}}`:                                                                 {draft: Draft201909},
+		`{"$schema": "https://json-schema.org/draft/2020-12/schema", "$vocabulary": {"https://json-schema.org/draft/2020-12/vocab/core": true, "https://json-schema.org/draft/2020-12/vocab/validation": true}}`: {draft: Draft202012, validation: true},
+	}
+	for data, want := range tests {
+		t.Run(data, func(t *testing.T) {
+			var metaSchema *Schema
+			if err := json.Unmarshal([]byte(data), &metaSchema); err != nil {
+				t.Fatal(err)
+			}
+			if got := dialectForMetaSchema(metaSchema); got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// A FormatChecker checks whether a string is valid according to a format (such as "date-time"). It
+// returns nil if the string is valid, or else an error describing why the string is invalid.
+type FormatChecker func(s string) error
+
+// A FormatRegistry maps the names of formats (the values of the "format" keyword) to the checkers
+// that assert them (see [draft-handrews-json-schema-validation-01 section
+// 7](https://tools.ietf.org/html/draft-handrews-json-schema-validation-01#section-7)).
+//
+// A FormatRegistry is safe for concurrent use by multiple goroutines.
+type FormatRegistry struct {
+	mu       sync.RWMutex
+	checkers map[string]FormatChecker
+}
+
+// NewFormatRegistry returns a new registry with checkers for all of the formats defined in
+// draft-07.
+func NewFormatRegistry() *FormatRegistry {
+	r := &FormatRegistry{checkers: make(map[string]FormatChecker, len(draft07Formats))}
+	for name, checker := range draft07Formats {
+		r.checkers[name] = checker
+	}
+	return r
+}
+
+// Register registers the checker for the named format, replacing any existing checker for the
+// format.
+func (r *FormatRegistry) Register(name string, checker FormatChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers[name] = checker
+}
+
+// Lookup returns the checker for the named format, or nil if there is none.
+func (r *FormatRegistry) Lookup(name string) FormatChecker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.checkers[name]
+}
+
+// DefaultFormatRegistry is the registry used by validators whose options do not specify one.
+var DefaultFormatRegistry = NewFormatRegistry()
+
+// RegisterFormat registers the checker for the named format in DefaultFormatRegistry.
+func RegisterFormat(name string, checker FormatChecker) {
+	DefaultFormatRegistry.Register(name, checker)
+}
+
+var draft07Formats = map[string]FormatChecker{
+	"date-time":             checkDateTime,
+	"date":                  checkDate,
+	"time":                  checkTime,
+	"email":                 checkEmail,
+	"idn-email":             checkEmail,
+	"hostname":              checkHostname,
+	"idn-hostname":          checkIDNHostname,
+	"ipv4":                  checkIPv4,
+	"ipv6":                  checkIPv6,
+	"uri":                   func(s string) error { return checkURI(s, true, false) },
+	"uri-reference":         func(s string) error { return checkURI(s, false, false) },
+	"iri":                   func(s string) error { return checkURI(s, true, true) },
+	"iri-reference":         func(s string) error { return checkURI(s, false, true) },
+	"uri-template":          checkURITemplate,
+	"json-pointer":          checkJSONPointer,
+	"relative-json-pointer": checkRelativeJSONPointer,
+	"regex":                 checkRegex,
+}
+
+var (
+	dateRegexp     = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	timeRegexp     = regexp.MustCompile(`^(\d{2}):(\d{2}):(\d{2})(\.\d+)?([Zz]|[+-](\d{2}):(\d{2}))$`)
+	dateTimeRegexp = regexp.MustCompile(`^([^Tt]+)[Tt](.+)$`)
+)
+
+// checkDateTime checks a "date-time" as defined in [RFC 3339](https://tools.ietf.org/html/rfc3339#section-5.6).
+func checkDateTime(s string) error {
+	m := dateTimeRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return errors.New("expected RFC 3339 date-time")
+	}
+	if err := checkDate(m[1]); err != nil {
+		return err
+	}
+	return checkTime(m[2])
+}
+
+// checkDate checks a "full-date" as defined in [RFC 3339](https://tools.ietf.org/html/rfc3339#section-5.6).
+func checkDate(s string) error {
+	m := dateRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return errors.New("expected RFC 3339 full-date")
+	}
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	if month < 1 || month > 12 {
+		return fmt.Errorf("invalid month %d", month)
+	}
+	// time.Date normalizes out-of-range days into the following month.
+	if day < 1 || time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Day() != day {
+		return fmt.Errorf("invalid day %d", day)
+	}
+	return nil
+}
+
+// checkTime checks a "full-time" as defined in [RFC 3339](https://tools.ietf.org/html/rfc3339#section-5.6).
+func checkTime(s string) error {
+	m := timeRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return errors.New("expected RFC 3339 full-time")
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	second, _ := strconv.Atoi(m[3])
+	if hour > 23 || minute > 59 || second > 60 /* leap second */ {
+		return errors.New("time out of range")
+	}
+	if m[6] != "" {
+		offsetHour, _ := strconv.Atoi(m[6])
+		offsetMinute, _ := strconv.Atoi(m[7])
+		if offsetHour > 23 || offsetMinute > 59 {
+			return errors.New("time offset out of range")
+		}
+	}
+	return nil
+}
+
+// checkEmail checks an "email" (or "idn-email") as defined in [RFC
+// 5322](https://tools.ietf.org/html/rfc5322#section-3.4.1).
+func checkEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return err
+	}
+	if addr.Address != s || addr.Name != "" {
+		return errors.New("expected only an email address")
+	}
+	return nil
+}
+
+// checkHostname checks a "hostname" as defined in [RFC 1123](https://tools.ietf.org/html/rfc1123#page-13).
+func checkHostname(s string) error {
+	return checkHostnameLabels(s, func(r rune) bool {
+		return r < utf8.RuneSelf && (r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+}
+
+// checkIDNHostname checks an "idn-hostname" (an internationalized hostname as defined in [RFC
+// 5890](https://tools.ietf.org/html/rfc5890#section-2.3.2.3)). Only the general structure is
+// checked; the IDNA2008 code point and contextual rules are not.
+func checkIDNHostname(s string) error {
+	return checkHostnameLabels(s, func(r rune) bool {
+		return r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+	})
+}
+
+func checkHostnameLabels(s string, validRune func(rune) bool) error {
+	if s == "" || utf8.RuneCountInString(s) > 253 {
+		return errors.New("hostname must be between 1 and 253 characters long")
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || utf8.RuneCountInString(label) > 63 {
+			return fmt.Errorf("hostname label %q must be between 1 and 63 characters long", label)
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("hostname label %q must not begin or end with a hyphen", label)
+		}
+		if r, _ := utf8.DecodeRuneInString(label); unicode.IsMark(r) {
+			return fmt.Errorf("hostname label %q must not begin with a combining mark", label)
+		}
+		for _, r := range label {
+			if !validRune(r) {
+				return fmt.Errorf("invalid character %q in hostname label", r)
+			}
+		}
+	}
+	return nil
+}
+
+// checkIPv4 checks an "ipv4" address in dotted-quad notation.
+func checkIPv4(s string) error {
+	if ip := net.ParseIP(s); ip == nil || ip.To4() == nil || strings.Contains(s, ":") {
+		return errors.New("expected IPv4 address in dotted-quad notation")
+	}
+	return nil
+}
+
+// checkIPv6 checks an "ipv6" address as defined in [RFC 4291](https://tools.ietf.org/html/rfc4291#section-2.2).
+func checkIPv6(s string) error {
+	if ip := net.ParseIP(s); ip == nil || !strings.Contains(s, ":") {
+		return errors.New("expected IPv6 address")
+	}
+	return nil
+}
+
+var uriSchemeRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+// checkURI checks a URI or URI reference (as defined in [RFC
+// 3986](https://tools.ietf.org/html/rfc3986)) or an IRI or IRI reference (as defined in [RFC
+// 3987](https://tools.ietf.org/html/rfc3987)), if iri is true. Only the characters and the presence
+// of the scheme (if absolute is true) are checked.
+func checkURI(s string, absolute, iri bool) error {
+	if absolute && !uriSchemeRegexp.MatchString(s) {
+		return errors.New("expected absolute URI with scheme")
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '%':
+			if i+2 >= len(s) || !isHexDigit(s[i+1]) || !isHexDigit(s[i+2]) {
+				return errors.New("invalid percent-encoding")
+			}
+		case r >= utf8.RuneSelf:
+			if !iri || r == utf8.RuneError {
+				return fmt.Errorf("invalid character %q", r)
+			}
+		case !isURIChar(byte(r)):
+			return fmt.Errorf("invalid character %q", r)
+		}
+		i += size
+	}
+	return nil
+}
+
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// isURIChar reports whether c is an unreserved or reserved character (as defined in [RFC 3986
+// section 2](https://tools.ietf.org/html/rfc3986#section-2)).
+func isURIChar(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || strings.IndexByte("-._~:/?#[]@!$&'()*+,;=", c) != -1
+}
+
+var uriTemplateExprRegexp = regexp.MustCompile(`^[+#./;?&=,!@|]?([A-Za-z0-9_.]|%[0-9A-Fa-f]{2})+(:[1-9][0-9]{0,3}|\*)?(,([A-Za-z0-9_.]|%[0-9A-Fa-f]{2})+(:[1-9][0-9]{0,3}|\*)?)*$`)
+
+// checkURITemplate checks a "uri-template" as defined in [RFC 6570](https://tools.ietf.org/html/rfc6570).
+func checkURITemplate(s string) error {
+	for s != "" {
+		i := strings.IndexAny(s, "{}")
+		if i == -1 {
+			break
+		}
+		if s[i] == '}' {
+			return errors.New("unmatched '}' in URI template")
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end == -1 {
+			return errors.New("unterminated expression in URI template")
+		}
+		if expr := s[i+1 : i+end]; !uriTemplateExprRegexp.MatchString(expr) {
+			return fmt.Errorf("invalid expression %q in URI template", expr)
+		}
+		s = s[i+end+1:]
+	}
+	return nil
+}
+
+// checkJSONPointer checks a "json-pointer" as defined in [RFC 6901](https://tools.ietf.org/html/rfc6901).
+func checkJSONPointer(s string) error {
+	_, err := ParseJSONPointer(s)
+	return err
+}
+
+// checkRelativeJSONPointer checks a "relative-json-pointer" as defined in
+// [draft-handrews-relative-json-pointer-01](https://tools.ietf.org/html/draft-handrews-relative-json-pointer-01).
+func checkRelativeJSONPointer(s string) error {
+	_, err := ParseRelativePointer(s)
+	return err
+}
+
+// checkRegex checks a "regex". Go regular expression syntax (which is similar to, but not the same
+// as, the ECMA 262 syntax specified for JSON Schema) is used.
+func checkRegex(s string) error {
+	_, err := regexp.Compile(s)
+	return err
+}
@@ -0,0 +1,102 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFormatCheckers(t *testing.T) {
+	tests := map[string]map[string]bool{
+		"date-time": {
+			"1963-06-19T08:30:06.283185Z": true,
+			"1990-12-31T15:59:60-08:00":   true,
+			"1963-06-19t08:30:06z":        true,
+			"1963-02-30T08:30:06Z":        false,
+			"1963-06-19T08:30:06":         false,
+			"06/19/1963 08:30:06 PST":     false,
+		},
+		"date":  {"2020-02-29": true, "2019-02-29": false, "2013-350": false},
+		"time":  {"08:30:06+01:00": true, "24:00:00Z": false, "01:01:01,1111": false},
+		"email": {"joe.bloggs@example.com": true, "Joe <joe@example.com>": false, "2962": false},
+		"hostname": {
+			"www.example.com":         true,
+			"xn--4gbwdl.xn--wgbh1c":   true,
+			"-starts-with-hyphen":     false,
+			"not_a_valid_host_name":   false,
+			"ends-with-empty-label..": false,
+		},
+		"idn-hostname": {"실례.테스트": true, "〮실례.테스트": false},
+		"ipv4":         {"192.168.0.1": true, "256.256.256.256": false, "::1": false},
+		"ipv6":         {"::1": true, "192.168.0.1": false, "::laptop": false},
+		"uri": {
+			"http://foo.bar/?baz=qux#quux":  true,
+			"urn:oasis:names:specification": true,
+			"//foo.bar/?baz=qux#quux":       false,
+			"http:// shouldfail.com":        false,
+			"http://foo.bar/%zz":            false,
+		},
+		"uri-reference": {"/abc": true, "#frag\\ment": false},
+		"iri":           {"http://ƒøø.ßår/": true, "âππ": false},
+		"iri-reference": {"âππ": true, "#ƒräg\\mênt": false},
+		"uri-template": {
+			"http://example.com/dictionary/{term:1}/{term}": true,
+			"http://example.com/{+path,x*}":                 true,
+			"http://example.com/dictionary/{term":           false,
+			"http://example.com/}":                          false,
+		},
+		"json-pointer":          {"": true, "/a~1b/~0": true, "/foo/bar~": false, "a": false},
+		"relative-json-pointer": {"0#": true, "2/0/baz": true, "/foo": false, "01": false},
+		"regex":                 {"^[a-z]+$": true, "^(abc]": false},
+	}
+	registry := NewFormatRegistry()
+	for format, values := range tests {
+		checker := registry.Lookup(format)
+		if checker == nil {
+			t.Errorf("no checker for format %q", format)
+			continue
+		}
+		for value, valid := range values {
+			if err := checker(value); (err == nil) != valid {
+				t.Errorf("format %q, value %q: got error %v, want valid %v", format, value, err, valid)
+			}
+		}
+	}
+}
+
+func TestValidate_format(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{"properties":{"a":{"format":"date"},"b":{"format":"even"}}}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	const instance = `{"a":"x","b":"abc"}`
+
+	t.Run("annotation only", func(t *testing.T) {
+		if err := Validate(&schema, []byte(instance)); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	})
+
+	t.Run("asserted", func(t *testing.T) {
+		formats := NewFormatRegistry()
+		formats.Register("even", func(s string) error {
+			if len(s)%2 != 0 {
+				return errors.New("odd length")
+			}
+			return nil
+		})
+		v, err := NewValidatorWithOptions(&schema, ValidatorOptions{AssertFormat: true, Formats: formats})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = v.Validate([]byte(instance))
+		verr, ok := err.(*ValidationError)
+		if !ok {
+			t.Fatalf("got error %v, want *ValidationError", err)
+		}
+		if len(verr.Causes) != 2 {
+			t.Errorf("got %d causes (%v), want 2", len(verr.Causes), verr)
+		}
+	})
+}
@@ -0,0 +1,241 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Pointer is a JSON Pointer (see [RFC 6901](https://tools.ietf.org/html/rfc6901)), consisting
+// of its unescaped reference tokens. Use ParseJSONPointer to parse a Pointer from its string
+// form.
+//
+// A Pointer can be evaluated against any JSON document, such as an instance being validated. The
+// document can be a decoded value (consisting of map[string]interface{}, []interface{} and scalar
+// values, as produced by encoding/json) or raw JSON.
+type Pointer []string
+
+// String returns the string form of the JSON Pointer (such as "/a/b~1c").
+func (p Pointer) String() string { return FormatJSONPointer(p) }
+
+// Get returns the value in the decoded JSON document that the pointer refers to.
+func (p Pointer) Get(doc interface{}) (interface{}, error) {
+	v := doc
+	for i, token := range p {
+		switch container := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = container[token]; !ok {
+				return nil, p.errorf(i, "object has no member %q", token)
+			}
+		case []interface{}:
+			index, err := arrayIndex(token, len(container))
+			if err != nil {
+				return nil, p.errorf(i, "%s", err)
+			}
+			if index == len(container) {
+				return nil, p.errorf(i, "array index %s is out of bounds", token)
+			}
+			v = container[index]
+		default:
+			return nil, p.errorf(i, "cannot dereference %q in a value of type %T", token, v)
+		}
+	}
+	return v, nil
+}
+
+// Set sets the value that the pointer refers to in the decoded JSON document, and it returns the
+// resulting document. Objects in the document are modified in place; arrays may be reallocated.
+//
+// The last reference token may refer to a nonexistent member of an object (which adds the member)
+// or to the element after the end of an array ("-" or the array's length, which appends the
+// element). If the pointer is empty, Set returns value.
+func (p Pointer) Set(doc interface{}, value interface{}) (interface{}, error) {
+	return p.set(doc, value, 0)
+}
+
+func (p Pointer) set(v interface{}, value interface{}, i int) (interface{}, error) {
+	if i == len(p) {
+		return value, nil
+	}
+	token := p[i]
+	switch container := v.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok && i != len(p)-1 {
+			return nil, p.errorf(i, "object has no member %q", token)
+		}
+		child, err := p.set(child, value, i+1)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(container))
+		if err != nil {
+			return nil, p.errorf(i, "%s", err)
+		}
+		if index == len(container) {
+			if i != len(p)-1 {
+				return nil, p.errorf(i, "array index %s is out of bounds", token)
+			}
+			return append(container, value), nil
+		}
+		child, err := p.set(container[index], value, i+1)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = child
+		return container, nil
+	default:
+		return nil, p.errorf(i, "cannot dereference %q in a value of type %T", token, v)
+	}
+}
+
+// GetRaw returns the raw JSON value in the raw JSON document that the pointer refers to.
+func (p Pointer) GetRaw(doc json.RawMessage) (json.RawMessage, error) {
+	for i, token := range p {
+		c, err := rawChild(doc, token)
+		if err != nil {
+			return nil, p.errorf(i, "%s", err)
+		}
+		if c.start == -1 {
+			return nil, p.errorf(i, "no value at %q", token)
+		}
+		doc = doc[c.start:c.end]
+	}
+	return doc, nil
+}
+
+// SetRaw is like Set, except that it operates on a raw JSON document. Only the value that the
+// pointer refers to is replaced (or added) in the document; the rest of the document (including
+// its formatting and the order of object members) is preserved. The document is not modified; a
+// new document is returned.
+func (p Pointer) SetRaw(doc json.RawMessage, value json.RawMessage) (json.RawMessage, error) {
+	if !json.Valid(value) {
+		return nil, fmt.Errorf("JSON Pointer %q: invalid JSON value to set", p)
+	}
+	return p.setRaw(doc, value, 0)
+}
+
+func (p Pointer) setRaw(doc, value json.RawMessage, i int) (json.RawMessage, error) {
+	if i == len(p) {
+		return value, nil
+	}
+	token := p[i]
+	c, err := rawChild(doc, token)
+	if err != nil {
+		return nil, p.errorf(i, "%s", err)
+	}
+
+	var insert []byte
+	start, end := c.start, c.end
+	if start == -1 {
+		if i != len(p)-1 {
+			return nil, p.errorf(i, "no value at %q", token)
+		}
+		// Add the member or element before the closing delimiter.
+		var buf bytes.Buffer
+		if c.n > 0 {
+			buf.WriteByte(',')
+		}
+		if c.object {
+			key, _ := json.Marshal(token)
+			buf.Write(key)
+			buf.WriteByte(':')
+		}
+		buf.Write(value)
+		insert = buf.Bytes()
+		start, end = c.closing, c.closing
+	} else {
+		if insert, err = p.setRaw(doc[start:end], value, i+1); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]byte, 0, len(doc)-(end-start)+len(insert))
+	result = append(result, doc[:start]...)
+	result = append(result, insert...)
+	result = append(result, doc[end:]...)
+	return result, nil
+}
+
+func (p Pointer) errorf(i int, format string, args ...interface{}) error {
+	return fmt.Errorf("JSON Pointer %q: at %q: %s", p, p[:i+1], fmt.Sprintf(format, args...))
+}
+
+// arrayIndex returns the array index that the reference token refers to in an array of length n.
+// It returns n for the "-" token (which refers to the nonexistent element after the last element).
+func arrayIndex(token string, n int) (int, error) {
+	if token == "-" {
+		return n, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index > n {
+		return 0, fmt.Errorf("array index %s is out of bounds", token)
+	}
+	return index, nil
+}
+
+// rawChildSpan describes the location of a child value in a raw JSON object or array.
+type rawChildSpan struct {
+	start, end int  // the span of the child value (or start == -1 if there is no such child)
+	closing    int  // the offset of the closing delimiter of the object or array
+	n          int  // the number of members or elements
+	object     bool // whether the container is an object (not an array)
+}
+
+// rawChild finds the member of a raw JSON object (or the element of a raw JSON array) that the
+// reference token refers to.
+func rawChild(doc []byte, token string) (c rawChildSpan, err error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	t, err := dec.Token()
+	if err != nil {
+		return c, err
+	}
+	switch t {
+	case json.Delim('{'):
+		c.object = true
+	case json.Delim('['):
+	default:
+		return c, fmt.Errorf("cannot dereference %q in a JSON value that is not an object or array", token)
+	}
+
+	c.start = -1
+	for ; dec.More(); c.n++ {
+		var key string
+		if c.object {
+			t, err := dec.Token()
+			if err != nil {
+				return c, err
+			}
+			key = t.(string)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return c, err
+		}
+		if (c.object && key == token) || (!c.object && strconv.Itoa(c.n) == token) {
+			c.end = int(dec.InputOffset())
+			c.start = c.end - len(value)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return c, err
+	}
+	c.closing = int(dec.InputOffset()) - 1
+
+	if !c.object && c.start == -1 {
+		if _, err := arrayIndex(token, c.n); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
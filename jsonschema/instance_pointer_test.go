@@ -0,0 +1,135 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const pointerTestDoc = `{
+  "foo": ["bar", "baz"],
+  "": 0,
+  "a/b": 1,
+  "m~n": 8,
+  "obj": {"x": {"y": null}}
+}`
+
+func TestPointer_Get(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(pointerTestDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		want    string // JSON
+		wantErr bool
+	}{
+		"":           {want: pointerTestDoc},
+		"/foo":       {want: `["bar","baz"]`},
+		"/foo/0":     {want: `"bar"`},
+		"/":          {want: `0`},
+		"/a~1b":      {want: `1`},
+		"/m~0n":      {want: `8`},
+		"/obj/x/y":   {want: `null`},
+		"/foo/2":     {wantErr: true},
+		"/foo/-":     {wantErr: true},
+		"/foo/01":    {wantErr: true},
+		"/nope":      {wantErr: true},
+		"/foo/0/bar": {wantErr: true},
+	}
+	for pointer, test := range tests {
+		t.Run(pointer, func(t *testing.T) {
+			p, err := ParseJSONPointer(pointer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := p.Get(doc)
+			gotRaw, errRaw := p.GetRaw(json.RawMessage(pointerTestDoc))
+			if test.wantErr {
+				if err == nil || errRaw == nil {
+					t.Fatalf("got errors %v and %v, want errors", err, errRaw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if errRaw != nil {
+				t.Fatal(errRaw)
+			}
+
+			var want interface{}
+			if err := json.Unmarshal([]byte(test.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Get: got %v, want %v", got, want)
+			}
+			var gotRawValue interface{}
+			if err := json.Unmarshal(gotRaw, &gotRawValue); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotRawValue, want) {
+				t.Errorf("GetRaw: got %s, want %s", gotRaw, test.want)
+			}
+		})
+	}
+}
+
+func TestPointer_Set(t *testing.T) {
+	tests := map[string]struct {
+		doc, value string
+		want       string // for Set, compared after decoding
+		wantRaw    string // for SetRaw, compared exactly
+		wantErr    bool
+	}{
+		"":       {doc: `{"a": 1}`, value: `2`, want: `2`, wantRaw: `2`},
+		"/a":     {doc: `{"b": 0, "a": 1}`, value: `2`, want: `{"a":2,"b":0}`, wantRaw: `{"b": 0, "a": 2}`},
+		"/c":     {doc: `{"b": 0, "a": 1}`, value: `[3]`, want: `{"a":1,"b":0,"c":[3]}`, wantRaw: `{"b": 0, "a": 1,"c":[3]}`},
+		"/c~1d":  {doc: `{}`, value: `true`, want: `{"c/d":true}`, wantRaw: `{"c/d":true}`},
+		"/a/1":   {doc: `{"a": [1, 2, 3]}`, value: `{}`, want: `{"a":[1,{},3]}`, wantRaw: `{"a": [1, {}, 3]}`},
+		"/a/-":   {doc: `{"a": [1]}`, value: `2`, want: `{"a":[1,2]}`, wantRaw: `{"a": [1,2]}`},
+		"/a/0":   {doc: `{"a": []}`, value: `2`, want: `{"a":[2]}`, wantRaw: `{"a": [2]}`},
+		"/a/b/c": {doc: `{"a": {"b": {"c": 1, "d": 2}}}`, value: `"x"`, want: `{"a":{"b":{"c":"x","d":2}}}`, wantRaw: `{"a": {"b": {"c": "x", "d": 2}}}`},
+		"/a/2":   {doc: `{"a": [1]}`, value: `2`, wantErr: true},
+		"/x/y":   {doc: `{"a": 1}`, value: `2`, wantErr: true},
+		"/a/x":   {doc: `{"a": 1}`, value: `2`, wantErr: true},
+	}
+	for pointer, test := range tests {
+		t.Run(pointer, func(t *testing.T) {
+			p, err := ParseJSONPointer(pointer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var doc, value interface{}
+			if err := json.Unmarshal([]byte(test.doc), &doc); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(test.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Set(doc, value)
+			gotRaw, errRaw := p.SetRaw(json.RawMessage(test.doc), json.RawMessage(test.value))
+			if test.wantErr {
+				if err == nil || errRaw == nil {
+					t.Fatalf("got errors %v and %v, want errors", err, errRaw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if errRaw != nil {
+				t.Fatal(errRaw)
+			}
+
+			if gotJSON, _ := json.Marshal(got); string(gotJSON) != test.want {
+				t.Errorf("Set: got %s, want %s", gotJSON, test.want)
+			}
+			if string(gotRaw) != test.wantRaw {
+				t.Errorf("SetRaw: got %s, want %s", gotRaw, test.wantRaw)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// A Keyword defines a custom keyword (such as "x-ui-hints"), which extends JSON Schema with a
+// keyword that is not defined by any draft. Custom keywords are registered with RegisterKeyword.
+//
+// The value of a registered custom keyword in a schema is parsed when the schema is unmarshaled and
+// stored in the schema's Extensions (keyed by the keyword), so that it can be retrieved as a typed
+// Go value. The raw values of unregistered keywords are stored in the schema's Unknown.
+type Keyword struct {
+	// Parse parses the raw JSON value of the keyword into the Go value that is stored in
+	// Schema.Extensions. If nil, the value is decoded with encoding/json into an interface{}.
+	//
+	// To contain subschemas, the Go value must hold them as *Schema values (which are unmarshaled
+	// from the raw JSON like any other schema), and Subschemas must return them.
+	Parse func(raw json.RawMessage) (interface{}, error)
+
+	// Subschemas returns the subschemas in the keyword's Go value (if any), with the reference
+	// tokens of their locations relative to the keyword's value. Walk visits these subschemas, so
+	// they are indexed by registries (for "$ref" resolution) and can be referred to. If nil, the
+	// keyword has no subschemas.
+	Subschemas func(value interface{}) []KeywordSubschema
+
+	// Validate validates an instance against the keyword's Go value, returning a non-nil error
+	// (whose message describes the failure) if the instance is invalid. The instance is decoded as
+	// for Validator.ValidateValue (numbers may be json.Number values). The isValid function
+	// reports whether a value is valid against one of the keyword's subschemas. If nil, the
+	// keyword is only an annotation and does not affect validation.
+	Validate func(value, instance interface{}, isValid func(schema *Schema, instance interface{}) bool) error
+}
+
+// A KeywordSubschema is a subschema in the value of a custom keyword.
+type KeywordSubschema struct {
+	Schema *Schema
+	Rel    []ReferenceToken // the location of the subschema relative to the keyword's value
+}
+
+var (
+	keywordsMu sync.RWMutex
+	keywords   = map[string]*Keyword{}
+)
+
+// RegisterKeyword registers the definition of the named custom keyword. Only the schemas that are
+// unmarshaled after the keyword is registered have its parsed value in their Extensions.
+//
+// It panics if the name is a keyword defined by JSON Schema (or a property that Schema represents,
+// such as "!go"), or if a keyword with the name is already registered.
+func RegisterKeyword(name string, keyword Keyword) {
+	if isStandardKeyword(name) {
+		panic(fmt.Sprintf("jsonschema: RegisterKeyword of standard keyword %q", name))
+	}
+	keywordsMu.Lock()
+	defer keywordsMu.Unlock()
+	if _, ok := keywords[name]; ok {
+		panic(fmt.Sprintf("jsonschema: RegisterKeyword called twice for keyword %q", name))
+	}
+	keywords[name] = &keyword
+}
+
+// UnregisterKeyword removes the definition of the named custom keyword (if it is registered), so
+// that it can be registered again. It is intended for tests.
+func UnregisterKeyword(name string) {
+	keywordsMu.Lock()
+	defer keywordsMu.Unlock()
+	delete(keywords, name)
+}
+
+// isStandardKeyword reports whether the name is a keyword defined by JSON Schema or a property that
+// Schema represents with a field. Custom keywords with such names would be encoded twice.
+func isStandardKeyword(name string) bool {
+	return schemaKeywords[name] || name == "contentSchema"
+}
+
+// LookupKeyword returns the definition of the named custom keyword, or nil if it is not
+// registered.
+func LookupKeyword(name string) *Keyword {
+	keywordsMu.RLock()
+	defer keywordsMu.RUnlock()
+	return keywords[name]
+}
+
+// hasKeywords reports whether any custom keywords are registered.
+func hasKeywords() bool {
+	keywordsMu.RLock()
+	defer keywordsMu.RUnlock()
+	return len(keywords) > 0
+}
+
+// parseExtensions parses the values of the registered custom keywords (given the members of the
+// raw JSON schema object) into the schema's Extensions.
+func (s *Schema) parseExtensions(members []rawMember) error {
+	for _, m := range members {
+		keyword := LookupKeyword(m.key)
+		if keyword == nil {
+			continue
+		}
+		var value interface{}
+		var err error
+		if keyword.Parse != nil {
+			value, err = keyword.Parse(append(json.RawMessage(nil), m.value...)) // the parser may retain it
+		} else {
+			err = json.Unmarshal(m.value, &value)
+		}
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to parse keyword %q", m.key))
+		}
+		if s.Extensions == nil {
+			s.Extensions = map[string]interface{}{}
+		}
+		s.Extensions[m.key] = value
+	}
+	return nil
+}
+
+// appendExtensions appends the values in the schema's Extensions to its JSON encoding (as
+// properties ordered by name).
+func (s *Schema) appendExtensions(data []byte) ([]byte, error) {
+	if len(s.Extensions) == 0 {
+		return data, nil
+	}
+	members, err := parseRawObject(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range s.extensionNames() {
+		value, err := json.Marshal(s.Extensions[name])
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to marshal keyword %q", name))
+		}
+		members = append(members, rawMember{key: name, value: value})
+	}
+	return formatRawObject(members), nil
+}
+
+// extensionNames returns the keywords in the schema's Extensions, sorted by name.
+func (s *Schema) extensionNames() []string {
+	names := make([]string, 0, len(s.Extensions))
+	for name := range s.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,132 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testUIHints struct {
+	Widget string `json:"widget"`
+}
+
+// registerTestKeywords registers the custom keywords used by the tests, and unregisters them when
+// the test finishes.
+func registerTestKeywords(t *testing.T) {
+	t.Cleanup(func() {
+		UnregisterKeyword("x-test-ui")
+		UnregisterKeyword("x-test-forbid")
+	})
+	RegisterKeyword("x-test-ui", Keyword{
+		Parse: func(raw json.RawMessage) (interface{}, error) {
+			var hints *testUIHints
+			err := json.Unmarshal(raw, &hints)
+			return hints, err
+		},
+	})
+	RegisterKeyword("x-test-forbid", Keyword{
+		Parse: func(raw json.RawMessage) (interface{}, error) {
+			var schema *Schema
+			err := json.Unmarshal(raw, &schema)
+			return schema, err
+		},
+		Subschemas: func(value interface{}) []KeywordSubschema {
+			return []KeywordSubschema{{Schema: value.(*Schema)}}
+		},
+		Validate: func(value, instance interface{}, isValid func(*Schema, interface{}) bool) error {
+			if isValid(value.(*Schema), instance) {
+				return errors.New("value matches the forbidden schema")
+			}
+			return nil
+		},
+	})
+}
+
+func TestKeyword(t *testing.T) {
+	registerTestKeywords(t)
+	data := `{
+  "definitions": {"a": {"const": 1}},
+  "x-test-forbid": {"$ref": "#/definitions/a"},
+  "x-test-ui": {"widget": "textarea"},
+  "x-unregistered": 1
+}`
+	schema, err := ParseSchema([]byte(data), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		if got, want := schema.Extensions["x-test-ui"], (&testUIHints{Widget: "textarea"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+		if _, ok := schema.Extensions["x-unregistered"]; ok {
+			t.Error("got unregistered keyword in Extensions")
+		}
+		if got, want := string(schema.Unknown["x-unregistered"]), "1"; got != want {
+			t.Errorf("got unregistered keyword value %q in Unknown, want %q", got, want)
+		}
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		marshaled, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"definitions":{"a":{"const":1}},"x-test-forbid":{"$ref":"#/definitions/a"},"x-test-ui":{"widget":"textarea"},"x-unregistered":1}`; string(marshaled) != want {
+			t.Errorf("got %s, want %s", marshaled, want)
+		}
+	})
+
+	t.Run("walk", func(t *testing.T) {
+		forbid := schema.Extensions["x-test-forbid"].(*Schema)
+		if got, want := schemaPointers(schema)[forbid], "/x-test-forbid"; got != want {
+			t.Errorf("got pointer %q, want %q", got, want)
+		}
+		if got, want := forbid.Pos.String(), "3:20"; got != want {
+			t.Errorf("got position %s, want %s", got, want)
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		v, err := NewValidator(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := v.Validate([]byte(`2`)); err != nil {
+			t.Errorf("got error %v, want valid", err)
+		}
+		err = v.Validate([]byte(`1`))
+		if err == nil {
+			t.Fatal("got valid, want error")
+		}
+		if cause := err.(*ValidationError).Causes[0]; cause.Keyword != "x-test-forbid" || cause.Message != "value matches the forbidden schema" {
+			t.Errorf("got error %+v", cause)
+		}
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		var schema *Schema
+		if err := json.Unmarshal([]byte(`{"x-test-ui": 1}`), &schema); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+}
+
+func TestRegisterKeyword_panics(t *testing.T) {
+	registerTestKeywords(t)
+	for name, keyword := range map[string]string{
+		"standard keyword": "properties",
+		"Go extension":     "!go",
+		"duplicate":        "x-test-ui",
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("got no panic, want panic")
+				}
+			}()
+			RegisterKeyword(keyword, Keyword{})
+		})
+	}
+}
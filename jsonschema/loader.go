@@ -0,0 +1,170 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A Loader loads JSON Schema documents that are referred to by "$ref" but are not otherwise known
+// (such as schemas stored in other files or served over HTTP).
+type Loader interface {
+	// Load returns the JSON Schema document identified by the absolute URI (which has no
+	// fragment).
+	Load(uri *url.URL) (*Schema, error)
+}
+
+// LoaderFunc is an adapter to allow the use of ordinary functions as Loaders.
+type LoaderFunc func(uri *url.URL) (*Schema, error)
+
+// Load implements Loader.
+func (f LoaderFunc) Load(uri *url.URL) (*Schema, error) { return f(uri) }
+
+// FileLoader loads JSON Schema documents identified by "file" URIs (such as
+// file:///path/to/schema.json) from the local file system.
+type FileLoader struct{}
+
+// Load implements Loader.
+func (FileLoader) Load(uri *url.URL) (*Schema, error) {
+	if uri.Scheme != "file" {
+		return nil, fmt.Errorf("unable to load schema %s from the file system (not a file URI)", uri)
+	}
+	filename := filepath.FromSlash(uri.Path)
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decodeSchema(f, filename)
+}
+
+// FSLoader loads JSON Schema documents from a file system (such as an embed.FS). The document
+// identified by a URI is read from the file at the URI's path relative to BaseURI.
+type FSLoader struct {
+	FS fs.FS
+
+	// BaseURI is the URI that corresponds to the root directory of FS. Only documents whose URIs
+	// are under BaseURI are loaded. It should end in "/". It must not be nil.
+	BaseURI *url.URL
+}
+
+// Load implements Loader.
+func (l FSLoader) Load(uri *url.URL) (*Schema, error) {
+	if l.BaseURI == nil {
+		return nil, fmt.Errorf("unable to load schema %s (FSLoader has no BaseURI)", uri)
+	}
+	base := l.BaseURI.String()
+	if !strings.HasPrefix(uri.String(), base) {
+		return nil, fmt.Errorf("unable to load schema %s (not under %s)", uri, base)
+	}
+	f, err := l.FS.Open(strings.TrimPrefix(uri.String(), base))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decodeSchema(f, uri.String())
+}
+
+// MapLoader loads JSON Schema documents from memory. It maps each document's URI (with no
+// fragment) to the document's JSON.
+type MapLoader map[string][]byte
+
+// Load implements Loader.
+func (l MapLoader) Load(uri *url.URL) (*Schema, error) {
+	data, ok := l[uri.String()]
+	if !ok {
+		return nil, fmt.Errorf("schema %s not found", uri)
+	}
+	return ParseSchema(data, uri.String())
+}
+
+// HTTPLoader loads JSON Schema documents identified by "http" and "https" URIs by fetching them.
+type HTTPLoader struct {
+	// Client is the HTTP client to use. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// Server, if non-nil, is the URL of a server (such as a local mirror or test server) to fetch
+	// all documents from instead of their own hosts. The path and query of each document's URI are
+	// appended to Server.
+	Server *url.URL
+}
+
+// Load implements Loader.
+func (l HTTPLoader) Load(uri *url.URL) (*Schema, error) {
+	if uri.Scheme != "http" && uri.Scheme != "https" {
+		return nil, fmt.Errorf("unable to fetch schema %s (not an HTTP or HTTPS URI)", uri)
+	}
+	u := *uri
+	if l.Server != nil {
+		u.Scheme = l.Server.Scheme
+		u.User = l.Server.User
+		u.Host = l.Server.Host
+		u.Path = strings.TrimSuffix(l.Server.Path, "/") + u.Path
+		u.RawPath = ""
+	}
+
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching schema %s: HTTP status %d", uri, resp.StatusCode)
+	}
+	return decodeSchema(resp.Body, uri.String())
+}
+
+// SchemeLoader loads JSON Schema documents using the loader for the scheme of each document's URI
+// (such as "file" or "https").
+type SchemeLoader map[string]Loader
+
+// Load implements Loader.
+func (l SchemeLoader) Load(uri *url.URL) (*Schema, error) {
+	loader, ok := l[uri.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unable to load schema %s (no loader for scheme %q)", uri, uri.Scheme)
+	}
+	return loader.Load(uri)
+}
+
+// ParseSchema parses the JSON Schema document and records the positions of its schemas (see
+// Schema.Pos), with filename (the file name or URI of the document, if known) as their file name.
+// Unlike json.Unmarshal, which records no positions, it should be used to read each document whose
+// positions are reported in errors. It returns an error if the document is null (which is not a
+// schema).
+func ParseSchema(data []byte, filename string) (*Schema, error) {
+	var schema *Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, ErrorAt(Position{Filename: filename}, errors.New("JSON Schema document is null"))
+	}
+	if !needsUpgrade(data) { // otherwise the schema was unmarshaled from the upgraded JSON
+		recordPositions(schema, data, "", Position{Filename: filename, Line: 1, Column: 1})
+	} else {
+		schema.SetFilename(filename)
+	}
+	return schema, nil
+}
+
+// decodeSchema reads and parses the JSON Schema document (see ParseSchema).
+func decodeSchema(r io.Reader, filename string) (*Schema, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(data, filename)
+}
@@ -0,0 +1,77 @@
+package jsonschema
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoaders(t *testing.T) {
+	const doc = `{"title":"a"}`
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.json"), []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileURI := (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, "a.json"))}).String()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/a.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(doc))
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		loader  Loader
+		uri     string
+		wantErr bool
+	}{
+		"FileLoader":                 {loader: FileLoader{}, uri: fileURI},
+		"FileLoader not found":       {loader: FileLoader{}, uri: fileURI + ".x", wantErr: true},
+		"FileLoader non-file URI":    {loader: FileLoader{}, uri: "http://example.com/a.json", wantErr: true},
+		"FSLoader":                   {loader: FSLoader{FS: fstest.MapFS{"s/a.json": {Data: []byte(doc)}}, BaseURI: mustParseURL(t, "http://example.com/")}, uri: "http://example.com/s/a.json"},
+		"FSLoader not under BaseURI": {loader: FSLoader{FS: fstest.MapFS{"s/a.json": {Data: []byte(doc)}}, BaseURI: mustParseURL(t, "http://example.com/")}, uri: "http://example.org/s/a.json", wantErr: true},
+		"FSLoader nil BaseURI":       {loader: FSLoader{FS: fstest.MapFS{"s/a.json": {Data: []byte(doc)}}}, uri: "http://example.com/s/a.json", wantErr: true},
+		"MapLoader":                  {loader: MapLoader{"urn:a": []byte(doc)}, uri: "urn:a"},
+		"MapLoader not found":        {loader: MapLoader{"urn:a": []byte(doc)}, uri: "urn:b", wantErr: true},
+		"MapLoader null":             {loader: MapLoader{"urn:a": []byte("null")}, uri: "urn:a", wantErr: true},
+		"HTTPLoader":                 {loader: HTTPLoader{Client: server.Client()}, uri: server.URL + "/schemas/a.json"},
+		"HTTPLoader with Server":     {loader: HTTPLoader{Client: server.Client(), Server: mustParseURL(t, server.URL)}, uri: "https://example.com/schemas/a.json"},
+		"HTTPLoader not found":       {loader: HTTPLoader{Client: server.Client()}, uri: server.URL + "/b.json", wantErr: true},
+		"SchemeLoader":               {loader: SchemeLoader{"urn": MapLoader{"urn:a": []byte(doc)}}, uri: "urn:a"},
+		"SchemeLoader no loader":     {loader: SchemeLoader{"urn": MapLoader{"urn:a": []byte(doc)}}, uri: "file:///a.json", wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema, err := test.loader.Load(mustParseURL(t, test.uri))
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("got nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if schema.Title == nil || *schema.Title != "a" {
+				t.Errorf("got schema %+v, want title %q", schema, "a")
+			}
+		})
+	}
+}
+
+func mustParseURL(t *testing.T, s string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
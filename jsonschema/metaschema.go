@@ -0,0 +1,318 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// CheckSchema checks that the raw JSON Schema document is valid against the draft-07 meta-schema
+// (http://json-schema.org/draft-07/schema). It returns nil if the document is valid. If the
+// document is invalid, the error is a *ValidationError whose causes describe each structural
+// problem (such as "minLength": "3" or an unknown "type" value) and its location in the document
+// (in InstanceLocation and InstancePosition).
+//
+// Draft-04 and draft-06 documents are upgraded to draft-07 (see Upgrade) before they are checked,
+// so the positions of their problems are not reported. Documents of later drafts are checked
+// against the draft-07 meta-schema too (which allows the keywords that it does not define).
+func CheckSchema(data []byte) error {
+	upgraded := needsUpgrade(data)
+	if upgraded {
+		var err error
+		if data, err = Upgrade(data, DefaultDraft); err != nil {
+			return err
+		}
+	}
+
+	v, err := metaSchemaValidator()
+	if err != nil {
+		return err
+	}
+	var instance interface{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return errors.WithMessage(err, "failed to decode JSON Schema")
+	}
+	err = v.ValidateValue(instance)
+	if verr, ok := err.(*ValidationError); ok && !upgraded {
+		setInstancePositions(verr, data)
+	}
+	return err
+}
+
+var metaSchema struct {
+	once      sync.Once
+	validator *Validator
+	err       error
+}
+
+// metaSchemaValidator returns the validator for the draft-07 meta-schema.
+func metaSchemaValidator() (*Validator, error) {
+	metaSchema.once.Do(func() {
+		var schema *Schema
+		if err := json.Unmarshal([]byte(draft07MetaSchema), &schema); err != nil {
+			metaSchema.err = errors.WithMessage(err, "failed to read draft-07 meta-schema")
+			return
+		}
+		metaSchema.validator, metaSchema.err = NewValidator(schema)
+	})
+	return metaSchema.validator, metaSchema.err
+}
+
+// setInstancePositions sets the InstancePosition of the error and its causes to the position of
+// their InstanceLocation in the raw JSON document.
+func setInstancePositions(err *ValidationError, data []byte) {
+	positions := valuePositions(data)
+	var set func(err *ValidationError)
+	set = func(err *ValidationError) {
+		err.InstancePosition = positions[encodeJSONPointer(err.InstanceLocation)]
+		for _, cause := range err.Causes {
+			set(cause)
+		}
+	}
+	set(err)
+}
+
+// draft07MetaSchema is the draft-07 meta-schema (a copy of
+// testdata/json-schema-draft-07-schema.json).
+const draft07MetaSchema = `{
+  "$id": "http://json-schema.org/draft-07/schema#",
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "default": true,
+  "definitions": {
+    "nonNegativeInteger": {
+      "minimum": 0,
+      "type": "integer"
+    },
+    "nonNegativeIntegerDefault0": {
+      "allOf": [
+        {
+          "$ref": "#/definitions/nonNegativeInteger"
+        },
+        {
+          "default": 0
+        }
+      ]
+    },
+    "schemaArray": {
+      "items": {
+        "$ref": "#"
+      },
+      "minItems": 1,
+      "type": "array"
+    },
+    "simpleTypes": {
+      "enum": [
+        "array",
+        "boolean",
+        "integer",
+        "null",
+        "number",
+        "object",
+        "string"
+      ]
+    },
+    "stringArray": {
+      "default": [],
+      "items": {
+        "type": "string"
+      },
+      "type": "array",
+      "uniqueItems": true
+    }
+  },
+  "properties": {
+    "$comment": {
+      "type": "string"
+    },
+    "$id": {
+      "format": "uri-reference",
+      "type": "string"
+    },
+    "$ref": {
+      "format": "uri-reference",
+      "type": "string"
+    },
+    "$schema": {
+      "format": "uri",
+      "type": "string"
+    },
+    "additionalItems": {
+      "$ref": "#"
+    },
+    "additionalProperties": {
+      "$ref": "#"
+    },
+    "allOf": {
+      "$ref": "#/definitions/schemaArray"
+    },
+    "anyOf": {
+      "$ref": "#/definitions/schemaArray"
+    },
+    "const": true,
+    "contains": {
+      "$ref": "#"
+    },
+    "contentEncoding": {
+      "type": "string"
+    },
+    "contentMediaType": {
+      "type": "string"
+    },
+    "default": true,
+    "definitions": {
+      "additionalProperties": {
+        "$ref": "#"
+      },
+      "default": {},
+      "type": "object"
+    },
+    "dependencies": {
+      "additionalProperties": {
+        "anyOf": [
+          {
+            "$ref": "#"
+          },
+          {
+            "$ref": "#/definitions/stringArray"
+          }
+        ]
+      },
+      "type": "object"
+    },
+    "description": {
+      "type": "string"
+    },
+    "else": {
+      "$ref": "#"
+    },
+    "enum": {
+      "items": true,
+      "minItems": 1,
+      "type": "array",
+      "uniqueItems": true
+    },
+    "examples": {
+      "items": true,
+      "type": "array"
+    },
+    "exclusiveMaximum": {
+      "type": "number"
+    },
+    "exclusiveMinimum": {
+      "type": "number"
+    },
+    "format": {
+      "type": "string"
+    },
+    "if": {
+      "$ref": "#"
+    },
+    "items": {
+      "anyOf": [
+        {
+          "$ref": "#"
+        },
+        {
+          "$ref": "#/definitions/schemaArray"
+        }
+      ],
+      "default": true
+    },
+    "maxItems": {
+      "$ref": "#/definitions/nonNegativeInteger"
+    },
+    "maxLength": {
+      "$ref": "#/definitions/nonNegativeInteger"
+    },
+    "maxProperties": {
+      "$ref": "#/definitions/nonNegativeInteger"
+    },
+    "maximum": {
+      "type": "number"
+    },
+    "minItems": {
+      "$ref": "#/definitions/nonNegativeIntegerDefault0"
+    },
+    "minLength": {
+      "$ref": "#/definitions/nonNegativeIntegerDefault0"
+    },
+    "minProperties": {
+      "$ref": "#/definitions/nonNegativeIntegerDefault0"
+    },
+    "minimum": {
+      "type": "number"
+    },
+    "multipleOf": {
+      "exclusiveMinimum": 0,
+      "type": "number"
+    },
+    "not": {
+      "$ref": "#"
+    },
+    "oneOf": {
+      "$ref": "#/definitions/schemaArray"
+    },
+    "pattern": {
+      "format": "regex",
+      "type": "string"
+    },
+    "patternProperties": {
+      "additionalProperties": {
+        "$ref": "#"
+      },
+      "default": {},
+      "propertyNames": {
+        "format": "regex"
+      },
+      "type": "object"
+    },
+    "properties": {
+      "additionalProperties": {
+        "$ref": "#"
+      },
+      "default": {},
+      "type": "object"
+    },
+    "propertyNames": {
+      "$ref": "#"
+    },
+    "readOnly": {
+      "default": false,
+      "type": "boolean"
+    },
+    "required": {
+      "$ref": "#/definitions/stringArray"
+    },
+    "then": {
+      "$ref": "#"
+    },
+    "title": {
+      "type": "string"
+    },
+    "type": {
+      "anyOf": [
+        {
+          "$ref": "#/definitions/simpleTypes"
+        },
+        {
+          "items": {
+            "$ref": "#/definitions/simpleTypes"
+          },
+          "minItems": 1,
+          "type": "array",
+          "uniqueItems": true
+        }
+      ]
+    },
+    "uniqueItems": {
+      "default": false,
+      "type": "boolean"
+    }
+  },
+  "title": "Core schema meta-schema",
+  "type": [
+    "object",
+    "boolean"
+  ]
+}`
@@ -0,0 +1,66 @@
+package jsonschema
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestDraft07MetaSchema(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/json-schema-draft-07-schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := draft07MetaSchema + "\n"; got != string(data) {
+		t.Error("draft07MetaSchema differs from testdata/json-schema-draft-07-schema.json")
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, data := range []string{
+			`true`,
+			`{"type": ["string", "null"], "minLength": 3}`,
+			`{"$schema": "http://json-schema.org/draft-04/schema#", "exclusiveMinimum": true, "minimum": 1}`,
+		} {
+			if err := CheckSchema([]byte(data)); err != nil {
+				t.Errorf("%s: %s", data, err)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		data := `{
+  "properties": {
+    "a": {"minLength": "3"},
+    "b": {"type": "strin"}
+  }
+}`
+		err := CheckSchema([]byte(data))
+		verr, ok := err.(*ValidationError)
+		if !ok {
+			t.Fatalf("got error %v, want *ValidationError", err)
+		}
+		got := map[string]string{}
+		for _, cause := range verr.Causes {
+			got[encodeJSONPointer(cause.InstanceLocation)] = cause.InstancePosition.String()
+		}
+		want := map[string]string{
+			"/properties/a/minLength": "3:24",
+			"/properties/b/type":      "4:19",
+		}
+		if len(got) != len(want) {
+			t.Fatalf("got errors %v, want errors at %v", verr.Causes, want)
+		}
+		for pointer, pos := range want {
+			if got[pointer] != pos {
+				t.Errorf("%s: got position %q, want %q", pointer, got[pointer], pos)
+			}
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		if err := CheckSchema([]byte(`{`)); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+}
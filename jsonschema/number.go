@@ -0,0 +1,178 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxExactOrder is the largest decimal order of magnitude (in either direction) of a JSON number
+// that is represented exactly as a big.Rat. It matches the limit of big.Rat.SetString, which
+// avoids the cost of computing excessively large powers of 10.
+const maxExactOrder = 1e6
+
+// number is the value of a decoded JSON number.
+//
+// Most numbers are represented exactly as a big.Rat. A number whose magnitude is greater than
+// 10^maxExactOrder (such as 1e100000000) or less than 10^-maxExactOrder is instead represented by
+// its decimal digits and exponent. Such a number is still compared correctly with the numbers in a
+// schema, because the magnitude of every float64 (other than 0) lies between these bounds.
+type number struct {
+	rat *big.Rat // the exact value (nil if the number is too large or too small)
+
+	// If rat is nil, the value is mant×10^exp (negated if neg), where mant has no trailing zeros.
+	neg  bool
+	huge bool // whether the magnitude is too large (otherwise it is too small)
+	mant *big.Int
+	exp  *big.Int
+}
+
+// toNumber returns the value of a decoded JSON number.
+func toNumber(instance interface{}) (number, bool) {
+	var r *big.Rat
+	switch n := instance.(type) {
+	case json.Number:
+		return parseNumber(string(n))
+	case float64:
+		r = floatRat(n)
+	case float32:
+		r = floatRat(float64(n))
+	case int:
+		r = new(big.Rat).SetInt64(int64(n))
+	case int64:
+		r = new(big.Rat).SetInt64(n)
+	}
+	return number{rat: r}, r != nil
+}
+
+// parseNumber parses a number in the JSON syntax.
+func parseNumber(s string) (number, bool) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	mantissa, exponent := s, "0"
+	if i := strings.IndexAny(s, "eE"); i != -1 {
+		mantissa, exponent = s[:i], s[i+1:]
+	}
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i != -1 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+		if fracPart == "" {
+			return number{}, false
+		}
+	}
+	exp, ok := new(big.Int).SetString(exponent, 10)
+	if !ok || intPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return number{}, false
+	}
+
+	// Normalize the number to mant×10^exp, where mant has no leading or trailing zeros.
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	trimmed := strings.TrimRight(digits, "0")
+	exp.Add(exp, big.NewInt(int64(len(digits)-len(trimmed)-len(fracPart))))
+	if trimmed == "" {
+		return number{rat: new(big.Rat)}, true
+	}
+	mant, _ := new(big.Int).SetString(trimmed, 10)
+	order := new(big.Int).Add(exp, big.NewInt(int64(len(trimmed))))
+	if order.CmpAbs(big.NewInt(maxExactOrder)) > 0 {
+		return number{neg: neg, huge: order.Sign() > 0, mant: mant, exp: exp}, true
+	}
+	// The exponent's magnitude is at most maxExactOrder plus the number of digits.
+	r := new(big.Rat).SetInt(mant)
+	if pow := pow10(new(big.Int).Abs(exp)); exp.Sign() >= 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow))
+	} else {
+		r.Quo(r, new(big.Rat).SetInt(pow))
+	}
+	if neg {
+		r.Neg(r)
+	}
+	return number{rat: r}, true
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n *big.Int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), n, nil)
+}
+
+func (n number) sign() int {
+	switch {
+	case n.rat != nil:
+		return n.rat.Sign()
+	case n.neg:
+		return -1
+	}
+	return 1
+}
+
+func (n number) isInt() bool {
+	if n.rat != nil {
+		return n.rat.IsInt()
+	}
+	return n.exp.Sign() >= 0
+}
+
+// cmp compares the number with x, which must be the value of a float64 (such as the value of a
+// schema keyword).
+func (n number) cmp(x *big.Rat) int {
+	switch {
+	case n.rat != nil:
+		return n.rat.Cmp(x)
+	case n.huge || x.Sign() == 0:
+		return n.sign()
+	}
+	// The magnitude of n is less than that of x.
+	return -x.Sign()
+}
+
+// equal reports whether the numbers have the same value.
+func (n number) equal(m number) bool {
+	if n.rat != nil || m.rat != nil {
+		return n.rat != nil && m.rat != nil && n.rat.Cmp(m.rat) == 0
+	}
+	return n.neg == m.neg && n.mant.Cmp(m.mant) == 0 && n.exp.Cmp(m.exp) == 0
+}
+
+// isMultipleOf reports whether the number is an integer multiple of x, which must be the value of
+// a positive float64.
+func (n number) isMultipleOf(x *big.Rat) bool {
+	if n.rat != nil {
+		return new(big.Rat).Quo(n.rat, x).IsInt()
+	}
+	if !n.huge {
+		return false // 0 < |n| < x
+	}
+
+	// n/x = mant×10^exp×q/p, where x = p/q in lowest terms.
+	p, q := x.Num(), x.Denom()
+	if n.exp.Sign() < 0 {
+		// The exponent's magnitude is less than the number of digits of mant.
+		d := new(big.Int).Mul(p, pow10(new(big.Int).Neg(n.exp)))
+		return new(big.Int).Mod(new(big.Int).Mul(n.mant, q), d).Sign() == 0
+	}
+	// p divides mant×10^exp if and only if p/gcd(p, 10^exp) divides mant. Because p has fewer than
+	// p.BitLen() factors 2 and 5, 10^exp can be replaced by a power of 10 with at most that exponent.
+	k := big.NewInt(int64(p.BitLen()))
+	if n.exp.Cmp(k) < 0 {
+		k = n.exp
+	}
+	g := new(big.Int).GCD(nil, nil, p, pow10(k))
+	return new(big.Int).Mod(n.mant, new(big.Int).Quo(p, g)).Sign() == 0
+}
+
+// floatRat returns the decimal value of f (as it would be written in a JSON document), not the
+// exact value of its binary representation. This makes keywords such as "multipleOf": 0.01 behave
+// as schema authors expect. It returns nil if f is not finite.
+func floatRat(f float64) *big.Rat {
+	n, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return n
+}
@@ -0,0 +1,45 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := map[string]struct {
+		a, b  string
+		equal bool
+	}{
+		"integer and fraction":  {a: "1", b: "1.00", equal: true},
+		"exponent":              {a: "1500", b: "1.5e3", equal: true},
+		"negative zero":         {a: "-0", b: "0e100000000", equal: true},
+		"huge":                  {a: "1e100000000", b: "0.001e100000003", equal: true},
+		"huge and exact":        {a: "1e1000001", b: "1e1000000", equal: false},
+		"huge different signs":  {a: "1e100000000", b: "-1e100000000", equal: false},
+		"tiny":                  {a: "-2.5e-100000000", b: "-25e-100000001", equal: true},
+		"huge exponent digits":  {a: "1e100000000000000000000", b: "10e99999999999999999999", equal: true},
+		"tiny and huge":         {a: "1e-100000000", b: "1e100000000", equal: false},
+		"different huge values": {a: "2e100000000", b: "3e100000000", equal: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, ok := toNumber(json.Number(test.a))
+			if !ok {
+				t.Fatalf("failed to parse %s", test.a)
+			}
+			b, ok := toNumber(json.Number(test.b))
+			if !ok {
+				t.Fatalf("failed to parse %s", test.b)
+			}
+			if equal := a.equal(b); equal != test.equal {
+				t.Errorf("got equal %v, want %v", equal, test.equal)
+			}
+		})
+	}
+
+	for _, s := range []string{"", "-", "1.", ".5", "1e", "1e+", "0x10", "1.5.2"} {
+		if _, ok := toNumber(json.Number(s)); ok {
+			t.Errorf("%q: got ok, want not ok", s)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// An OutputFormat is one of the standardized formats for describing the result of validating an
+// instance (see [JSON Schema Core, section
+// 10](https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.10)).
+type OutputFormat string
+
+// The standardized output formats.
+const (
+	FlagOutput     OutputFormat = "flag"     // only whether the instance is valid
+	BasicOutput    OutputFormat = "basic"    // a flat list of errors
+	DetailedOutput OutputFormat = "detailed" // a hierarchy of errors, condensed
+	VerboseOutput  OutputFormat = "verbose"  // a hierarchy of errors, uncondensed
+)
+
+// An OutputUnit is a node in the result of validating an instance, in one of the standardized
+// output formats. It marshals to the JSON structure specified for that output format.
+//
+// Only failed assertions are described, so all units other than the root of a result for a valid
+// instance have Valid == false.
+type OutputUnit struct {
+	Valid                   bool          `json:"valid"`
+	KeywordLocation         string        `json:"keywordLocation"`
+	AbsoluteKeywordLocation string        `json:"absoluteKeywordLocation,omitempty"`
+	InstanceLocation        string        `json:"instanceLocation"`
+	Error                   string        `json:"error,omitempty"`
+	Errors                  []*OutputUnit `json:"errors,omitempty"`
+
+	flag bool // only the Valid field is marshaled
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u *OutputUnit) MarshalJSON() ([]byte, error) {
+	if u.flag {
+		return json.Marshal(struct {
+			Valid bool `json:"valid"`
+		}{Valid: u.Valid})
+	}
+	type outputUnit2 OutputUnit
+	return json.Marshal((*outputUnit2)(u))
+}
+
+// Output returns the result of validating an instance in the output format. The err argument is
+// the error returned by (*Validator).Validate (or another validation function): either nil (if the
+// instance is valid) or a *ValidationError.
+func Output(err error, format OutputFormat) (*OutputUnit, error) {
+	root := &OutputUnit{Valid: true}
+	var verr *ValidationError
+	if err != nil {
+		var ok bool
+		verr, ok = err.(*ValidationError)
+		if !ok {
+			return nil, errors.WithMessage(err, "unable to produce output for non-validation error")
+		}
+		root = newOutputUnit(verr)
+	}
+
+	switch format {
+	case FlagOutput:
+		root.flag = true
+	case BasicOutput:
+		if verr != nil {
+			root.Errors = nil
+			var flatten func(e *ValidationError)
+			flatten = func(e *ValidationError) {
+				root.Errors = append(root.Errors, newOutputUnit(e))
+				for _, cause := range e.Causes {
+					flatten(cause)
+				}
+			}
+			for _, cause := range verr.Causes {
+				flatten(cause)
+			}
+		}
+	case DetailedOutput:
+		if verr != nil {
+			root.Errors = detailedOutputUnits(verr.Causes)
+		}
+	case VerboseOutput:
+		if verr != nil {
+			root.Errors = verboseOutputUnits(verr.Causes)
+		}
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+	return root, nil
+}
+
+// newOutputUnit returns the output unit for e, without any nested errors.
+func newOutputUnit(e *ValidationError) *OutputUnit {
+	u := &OutputUnit{
+		KeywordLocation:  encodeJSONPointer(e.KeywordLocation),
+		InstanceLocation: encodeJSONPointer(e.InstanceLocation),
+		Error:            e.Message,
+	}
+	// The absolute location is only meaningful if the schema has a base URI.
+	if base := e.AbsoluteKeywordLocation.Base; base != nil && (base.Scheme != "" || base.Host != "" || base.Path != "") {
+		u.AbsoluteKeywordLocation = e.AbsoluteKeywordLocation.String()
+	}
+	return u
+}
+
+func verboseOutputUnits(errs []*ValidationError) []*OutputUnit {
+	units := make([]*OutputUnit, len(errs))
+	for i, e := range errs {
+		units[i] = newOutputUnit(e)
+		units[i].Errors = verboseOutputUnits(e.Causes)
+	}
+	return units
+}
+
+// detailedOutputUnits is like verboseOutputUnits, except that errors with exactly 1 cause are
+// replaced by their cause.
+func detailedOutputUnits(errs []*ValidationError) []*OutputUnit {
+	units := make([]*OutputUnit, len(errs))
+	for i, e := range errs {
+		for len(e.Causes) == 1 {
+			e = e.Causes[0]
+		}
+		units[i] = newOutputUnit(e)
+		units[i].Errors = detailedOutputUnits(e.Causes)
+	}
+	return units
+}
@@ -0,0 +1,63 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/internal/testutil"
+)
+
+func TestOutput(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{
+  "$id": "http://example.com/s.json",
+  "properties": {
+    "a": {"allOf": [{"$ref": "#/definitions/b"}]},
+    "c": {"anyOf": [{"type": "string"}, {"type": "boolean"}]}
+  },
+  "definitions": {"b": {"minLength": 2}}
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	validationErr := Validate(&schema, []byte(`{"a":"x","c":1}`))
+	if validationErr == nil {
+		t.Fatal("got valid, want invalid")
+	}
+
+	const (
+		unitA    = `{"valid":false,"keywordLocation":"/properties/a/allOf","absoluteKeywordLocation":"http://example.com/s.json#/properties/a/allOf","instanceLocation":"/a","error":"value is not valid against all of the allOf schemas"`
+		unitA0   = `{"valid":false,"keywordLocation":"/properties/a/allOf/0/$ref/minLength","absoluteKeywordLocation":"http://example.com/s.json#/definitions/b/minLength","instanceLocation":"/a","error":"string is shorter than the minimum length 2"`
+		unitC    = `{"valid":false,"keywordLocation":"/properties/c/anyOf","absoluteKeywordLocation":"http://example.com/s.json#/properties/c/anyOf","instanceLocation":"/c","error":"value is not valid against any of the anyOf schemas"`
+		unitC0   = `{"valid":false,"keywordLocation":"/properties/c/anyOf/0/type","absoluteKeywordLocation":"http://example.com/s.json#/properties/c/anyOf/0/type","instanceLocation":"/c","error":"expected string, but got number"`
+		unitC1   = `{"valid":false,"keywordLocation":"/properties/c/anyOf/1/type","absoluteKeywordLocation":"http://example.com/s.json#/properties/c/anyOf/1/type","instanceLocation":"/c","error":"expected boolean, but got number"`
+		rootUnit = `"valid":false,"keywordLocation":"","absoluteKeywordLocation":"http://example.com/s.json","instanceLocation":"","error":"instance is invalid"`
+	)
+	tests := map[OutputFormat]struct {
+		err  error
+		want string
+	}{
+		FlagOutput:     {err: validationErr, want: `{"valid":false}`},
+		BasicOutput:    {err: validationErr, want: `{` + rootUnit + `,"errors":[` + unitA + `},` + unitA0 + `},` + unitC + `},` + unitC0 + `},` + unitC1 + `}]}`},
+		DetailedOutput: {err: validationErr, want: `{` + rootUnit + `,"errors":[` + unitA0 + `},` + unitC + `,"errors":[` + unitC0 + `},` + unitC1 + `}]}]}`},
+		VerboseOutput:  {err: validationErr, want: `{` + rootUnit + `,"errors":[` + unitA + `,"errors":[` + unitA0 + `}]},` + unitC + `,"errors":[` + unitC0 + `},` + unitC1 + `}]}]}`},
+		"valid":        {err: nil, want: `{"valid":true,"keywordLocation":"","instanceLocation":""}`},
+	}
+	for format, test := range tests {
+		t.Run(string(format), func(t *testing.T) {
+			if format == "valid" {
+				format = BasicOutput
+			}
+			out, err := Output(test.err, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := testutil.CanonicalJSON([]byte(test.want)); string(testutil.CanonicalJSON(got)) != string(want) {
+				t.Errorf("got  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
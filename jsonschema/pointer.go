@@ -0,0 +1,89 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseJSONPointer parses a JSON Pointer (such as "/definitions/a~1b") and returns its unescaped
+// reference tokens (such as ["definitions", "a/b"]). See [RFC
+// 6901](https://tools.ietf.org/html/rfc6901).
+//
+// The empty string is a valid JSON Pointer that refers to the whole document; for it,
+// ParseJSONPointer returns an empty, non-nil Pointer.
+func ParseJSONPointer(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON Pointer %q (must be empty or begin with \"/\")", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		// Check that each "~" is part of a valid escape sequence ("~0" or "~1").
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				continue
+			}
+			if j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1') {
+				return nil, fmt.Errorf("invalid JSON Pointer %q (invalid escape sequence in %q)", s, token)
+			}
+			j++
+		}
+		tokens[i] = jsonPointerUnescaper.Replace(token)
+	}
+	return Pointer(tokens), nil
+}
+
+// FormatJSONPointer returns the JSON Pointer consisting of the reference tokens, escaping "~" and
+// "/" in each token (as "~0" and "~1"). It is the inverse of ParseJSONPointer.
+func FormatJSONPointer(tokens []string) string {
+	var buf strings.Builder
+	for _, token := range tokens {
+		buf.WriteByte('/')
+		buf.WriteString(jsonPointerEscaper.Replace(token))
+	}
+	return buf.String()
+}
+
+// ParseJSONPointerFragment parses a JSON Pointer from its URI fragment identifier representation
+// (such as "#/definitions/a%20b", with or without the leading "#"), in which characters that are
+// not allowed in URI fragments are percent-encoded. See [RFC 6901, section
+// 6](https://tools.ietf.org/html/rfc6901#section-6).
+func ParseJSONPointerFragment(fragment string) (Pointer, error) {
+	s, err := url.PathUnescape(strings.TrimPrefix(fragment, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON Pointer URI fragment %q: %s", fragment, err)
+	}
+	return ParseJSONPointer(s)
+}
+
+// FormatJSONPointerFragment returns the URI fragment identifier representation (with a leading
+// "#") of the JSON Pointer consisting of the reference tokens. It is the inverse of
+// ParseJSONPointerFragment.
+func FormatJSONPointerFragment(tokens []string) string {
+	return "#" + (&url.URL{Fragment: FormatJSONPointer(tokens)}).EscapedFragment()
+}
+
+// ReferenceTokensPointer returns the JSON Pointer consisting of the reference tokens (using the
+// decimal representation of array indexes). It can be used to evaluate the locations in a
+// ValidationError against the instance.
+func ReferenceTokensPointer(tokens []ReferenceToken) Pointer {
+	p := make(Pointer, len(tokens))
+	for i, token := range tokens {
+		p[i] = token.String()
+	}
+	return p
+}
+
+// encodeJSONPointer returns the JSON Pointer (with a leading "/" if non-empty) for the reference
+// tokens.
+func encodeJSONPointer(tokens []ReferenceToken) string {
+	return FormatJSONPointer(ReferenceTokensPointer(tokens))
+}
+
+var (
+	jsonPointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
@@ -0,0 +1,60 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSONPointer(t *testing.T) {
+	tests := map[string]struct {
+		pointer  string
+		fragment string
+		tokens   Pointer
+	}{
+		"whole document":  {pointer: "", fragment: "#", tokens: Pointer{}},
+		"simple":          {pointer: "/definitions/a", fragment: "#/definitions/a", tokens: Pointer{"definitions", "a"}},
+		"slash":           {pointer: "/properties/a~1b", fragment: "#/properties/a~1b", tokens: Pointer{"properties", "a/b"}},
+		"tilde":           {pointer: "/properties/a~0b", fragment: "#/properties/a~0b", tokens: Pointer{"properties", "a~b"}},
+		"escape order":    {pointer: "/~01", fragment: "#/~01", tokens: Pointer{"~1"}},
+		"empty token":     {pointer: "/", fragment: "#/", tokens: Pointer{""}},
+		"percent":         {pointer: "/a%b", fragment: "#/a%25b", tokens: Pointer{"a%b"}},
+		"space and quote": {pointer: `/a b"c`, fragment: "#/a%20b%22c", tokens: Pointer{`a b"c`}},
+		"array index":     {pointer: "/items/0", fragment: "#/items/0", tokens: Pointer{"items", "0"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tokens, err := ParseJSONPointer(test.pointer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tokens, test.tokens) {
+				t.Errorf("ParseJSONPointer: got %q, want %q", tokens, test.tokens)
+			}
+			if got := FormatJSONPointer(test.tokens); got != test.pointer {
+				t.Errorf("FormatJSONPointer: got %q, want %q", got, test.pointer)
+			}
+
+			tokens, err = ParseJSONPointerFragment(test.fragment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tokens, test.tokens) {
+				t.Errorf("ParseJSONPointerFragment: got %q, want %q", tokens, test.tokens)
+			}
+			if got := FormatJSONPointerFragment(test.tokens); got != test.fragment {
+				t.Errorf("FormatJSONPointerFragment: got %q, want %q", got, test.fragment)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, pointer := range []string{"a", "/a~", "/a~2", "#/a"} {
+			if _, err := ParseJSONPointer(pointer); err == nil {
+				t.Errorf("%q: got nil error, want error", pointer)
+			}
+		}
+		if _, err := ParseJSONPointerFragment("#/a%zz"); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+}
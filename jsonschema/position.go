@@ -0,0 +1,337 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// A Position is a location in the source of a JSON Schema document. It is valid if Line > 0.
+type Position struct {
+	Filename string // the file name or URI of the document, if known
+	Offset   int    // byte offset, starting at 0
+	Line     int    // line number, starting at 1
+	Column   int    // column number (byte count), starting at 1
+}
+
+// IsValid reports whether the position is valid.
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// String returns the position in one of the forms "file:line:column", "line:column", "file" or
+// "-" (if the position is invalid and has no file name).
+func (p Position) String() string {
+	s := p.Filename
+	if p.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
+}
+
+// ErrorAt returns err prefixed with the position (if it is known). It is used for all errors about
+// a location in a schema document, so that they are reported in the same form.
+func ErrorAt(pos Position, err error) error {
+	if !pos.IsValid() && pos.Filename == "" {
+		return err
+	}
+	return errors.WithMessage(err, pos.String())
+}
+
+// SetFilename sets the Filename of the positions of the schema and all of its subschemas (and of
+// their keyword values). ParseSchema sets it to the file name or URI of the document it parses.
+func (s *Schema) SetFilename(filename string) {
+	Walk(filenameSetter(filename), s)
+}
+
+// filenameSetter implements Visitor by setting the filename of each schema's positions.
+type filenameSetter string
+
+// Visit implements Visitor.
+func (f filenameSetter) Visit(schema *Schema, rel []ReferenceToken) Visitor {
+	if schema == nil {
+		return nil
+	}
+	schema.Pos.Filename = string(f)
+	for keyword, pos := range schema.KeywordPos {
+		pos.Filename = string(f)
+		schema.KeywordPos[keyword] = pos
+	}
+	return f
+}
+
+// recordPositions records the positions of the schema and all of its subschemas (and of their
+// keyword values) in data, which is raw JSON that contains the schema at the JSON Pointer base. The
+// origin is the position of the start of data in the document.
+//
+// It scans the whole document, so it is called once for each document (by ParseSchema), not for
+// each subschema as it is unmarshaled.
+//
+// Positions are recorded on a best-effort basis: if data can't be scanned, no positions are
+// recorded.
+func recordPositions(schema *Schema, data []byte, base string, origin Position) {
+	sc := positionScanner{data: data, offsets: map[string]int{}, keys: map[string][]string{}}
+	if err := sc.value(""); err != nil {
+		return
+	}
+
+	position := offsetPositioner(data, origin)
+	for s, pointer := range schemaPointers(schema) {
+		pointer = base + pointer
+		offset, ok := sc.offsets[pointer]
+		if !ok {
+			continue
+		}
+		s.Pos = position(offset)
+		s.KeywordPos = make(map[string]Position, len(sc.keys[pointer]))
+		for _, keyword := range sc.keys[pointer] {
+			s.KeywordPos[keyword] = position(sc.offsets[pointer+"/"+jsonPointerEscaper.Replace(keyword)])
+		}
+	}
+}
+
+// offsetPositioner returns a function that returns the position of a byte offset in data, which
+// starts at the position origin in the document.
+func offsetPositioner(data []byte, origin Position) func(offset int) Position {
+	var lines []int // the offset of the start of each line
+	lines = append(lines, 0)
+	for i, c := range data {
+		if c == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return func(offset int) Position {
+		line := sort.Search(len(lines), func(i int) bool { return lines[i] > offset }) // 1-based
+		pos := Position{Filename: origin.Filename, Offset: origin.Offset + offset, Line: origin.Line + line - 1, Column: offset - lines[line-1] + 1}
+		if line == 1 {
+			pos.Column += origin.Column - 1
+		}
+		return pos
+	}
+}
+
+// valuePositions returns the position of each value in the raw JSON document, keyed by JSON
+// Pointer. If data can't be scanned, it returns nil.
+func valuePositions(data []byte) map[string]Position {
+	sc := positionScanner{data: data, offsets: map[string]int{}, keys: map[string][]string{}}
+	if err := sc.value(""); err != nil {
+		return nil
+	}
+	position := offsetPositioner(data, Position{Line: 1, Column: 1})
+	positions := make(map[string]Position, len(sc.offsets))
+	for pointer, offset := range sc.offsets {
+		positions[pointer] = position(offset)
+	}
+	return positions
+}
+
+// positionScanner records the offset of each value in a JSON document.
+type positionScanner struct {
+	data    []byte
+	i       int
+	offsets map[string]int      // the offset of each value, keyed by JSON Pointer
+	keys    map[string][]string // the property names of each object, keyed by JSON Pointer
+}
+
+func (s *positionScanner) value(pointer string) error {
+	s.skipSpace()
+	if s.i >= len(s.data) {
+		return s.errorf("unexpected end of JSON")
+	}
+	s.offsets[pointer] = s.i
+	switch s.data[s.i] {
+	case '{':
+		s.i++
+		keys := []string{}
+		for {
+			s.skipSpace()
+			if s.consume('}') {
+				break
+			}
+			key, err := s.string()
+			if err != nil {
+				return err
+			}
+			s.skipSpace()
+			if !s.consume(':') {
+				return s.errorf("expected ':'")
+			}
+			if err := s.value(pointer + "/" + jsonPointerEscaper.Replace(key)); err != nil {
+				return err
+			}
+			keys = append(keys, key)
+			s.skipSpace()
+			if !s.consume(',') && (s.i >= len(s.data) || s.data[s.i] != '}') {
+				return s.errorf("expected ',' or '}'")
+			}
+		}
+		s.keys[pointer] = keys
+	case '[':
+		s.i++
+		for index := 0; ; index++ {
+			s.skipSpace()
+			if s.consume(']') {
+				break
+			}
+			if err := s.value(fmt.Sprintf("%s/%d", pointer, index)); err != nil {
+				return err
+			}
+			s.skipSpace()
+			if !s.consume(',') && (s.i >= len(s.data) || s.data[s.i] != ']') {
+				return s.errorf("expected ',' or ']'")
+			}
+		}
+	case '"':
+		if _, err := s.string(); err != nil {
+			return err
+		}
+	default:
+		// A number, true, false or null.
+		for s.i < len(s.data) && !isJSONDelimiter(s.data[s.i]) {
+			s.i++
+		}
+	}
+	return nil
+}
+
+// scanObjectMembers returns the members of the JSON object in data, in order. It is faster than
+// parseRawObject, but data must be valid JSON (such as the data passed to an UnmarshalJSON
+// method). If data is not an object, it returns no members.
+func scanObjectMembers(data []byte) ([]rawMember, error) {
+	s := positionScanner{data: data}
+	s.skipSpace()
+	if !s.consume('{') {
+		return nil, nil
+	}
+	var members []rawMember
+	for {
+		s.skipSpace()
+		if s.consume('}') {
+			return members, nil
+		}
+		key, err := s.string()
+		if err != nil {
+			return nil, err
+		}
+		s.skipSpace()
+		if !s.consume(':') {
+			return nil, s.errorf("expected ':'")
+		}
+		s.skipSpace()
+		start := s.i
+		if err := s.skipValue(); err != nil {
+			return nil, err
+		}
+		members = append(members, rawMember{key: key, value: data[start:s.i]})
+		s.skipSpace()
+		if !s.consume(',') && (s.i >= len(s.data) || s.data[s.i] != '}') {
+			return nil, s.errorf("expected ',' or '}'")
+		}
+	}
+}
+
+// skipValue advances past the value that starts at the current offset, without recording the
+// offsets of its values.
+func (s *positionScanner) skipValue() error {
+	if s.i >= len(s.data) {
+		return s.errorf("unexpected end of JSON")
+	}
+	switch s.data[s.i] {
+	case '{', '[':
+		depth := 0
+		for ; s.i < len(s.data); s.i++ {
+			switch s.data[s.i] {
+			case '"':
+				s.skipString()
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					s.i++
+					return nil
+				}
+			}
+		}
+		return s.errorf("unexpected end of JSON")
+	case '"':
+		s.skipString()
+		if s.i >= len(s.data) {
+			return s.errorf("unterminated string")
+		}
+		s.i++
+	default:
+		for s.i < len(s.data) && !isJSONDelimiter(s.data[s.i]) {
+			s.i++
+		}
+	}
+	return nil
+}
+
+// skipString advances from the opening quote of a JSON string to its closing quote (or to the end
+// of data, if the string is unterminated).
+func (s *positionScanner) skipString() {
+	for s.i++; s.i < len(s.data) && s.data[s.i] != '"'; s.i++ {
+		if s.data[s.i] == '\\' {
+			s.i++
+		}
+	}
+}
+
+// string scans a JSON string and returns its unquoted value.
+func (s *positionScanner) string() (string, error) {
+	start := s.i
+	if !s.consume('"') {
+		return "", s.errorf("expected string")
+	}
+	var escaped bool
+	for ; s.i < len(s.data); s.i++ {
+		switch s.data[s.i] {
+		case '\\':
+			escaped = true
+			s.i++
+		case '"':
+			s.i++
+			quoted := s.data[start:s.i]
+			if !escaped {
+				return string(quoted[1 : len(quoted)-1]), nil
+			}
+			var str string
+			err := json.Unmarshal(quoted, &str)
+			return str, err
+		}
+	}
+	return "", s.errorf("unterminated string")
+}
+
+func (s *positionScanner) skipSpace() {
+	for s.i < len(s.data) && (s.data[s.i] == ' ' || s.data[s.i] == '\t' || s.data[s.i] == '\r' || s.data[s.i] == '\n') {
+		s.i++
+	}
+}
+
+// consume advances past the byte c if it is next.
+func (s *positionScanner) consume(c byte) bool {
+	if s.i < len(s.data) && s.data[s.i] == c {
+		s.i++
+		return true
+	}
+	return false
+}
+
+func (s *positionScanner) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at offset %d: %s", s.i, fmt.Sprintf(format, args...))
+}
+
+func isJSONDelimiter(c byte) bool {
+	switch c {
+	case ',', ']', '}', ' ', '\t', '\r', '\n':
+		return true
+	}
+	return false
+}
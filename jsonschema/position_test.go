@@ -0,0 +1,79 @@
+package jsonschema
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPositions(t *testing.T) {
+	data := `{
+  "type": "object",
+  "properties": {
+    "a": {"type": "string"},
+    "b~/": {
+      "items": [true, {"minimum": 1}]
+    }
+  },
+  "definitions": {"c": {"pattern": "x"}}
+}`
+	schema, err := ParseSchema([]byte(data), "schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	props := *schema.Properties
+	tests := map[string]struct {
+		got  Position
+		want string
+	}{
+		"root":                {got: schema.Pos, want: "schema.json:1:1"},
+		"root keyword":        {got: schema.KeywordPos["type"], want: "schema.json:2:11"},
+		"property":            {got: props["a"].Pos, want: "schema.json:4:10"},
+		"property keyword":    {got: props["a"].KeywordPos["type"], want: "schema.json:4:19"},
+		"escaped property":    {got: props["b~/"].Pos, want: "schema.json:5:12"},
+		"boolean item":        {got: props["b~/"].Items.Schemas[0].Pos, want: "schema.json:6:17"},
+		"item keyword":        {got: props["b~/"].Items.Schemas[1].KeywordPos["minimum"], want: "schema.json:6:35"},
+		"definition":          {got: (*schema.Definitions)["c"].Pos, want: "schema.json:9:24"},
+		"definition keyword":  {got: (*schema.Definitions)["c"].KeywordPos["pattern"], want: "schema.json:9:36"},
+		"unknown keyword":     {got: schema.KeywordPos["x"], want: "-"},
+		"position of no file": {got: Position{Line: 2, Column: 3}, want: "2:3"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.got.String(); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPositions_errors(t *testing.T) {
+	loader := MapLoader{
+		"http://example.com/a.json": []byte("{\n  \"properties\": {\n    \"b\": {\"$ref\": \"#/definitions/missing\"}\n  }\n}"),
+	}
+	uri, _ := url.Parse("http://example.com/a.json")
+	schema, err := loader.Load(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewValidatorWithOptions(schema, ValidatorOptions{Loader: loader})
+	if want := "http://example.com/a.json:3:19: "; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("got error %v, want prefix %q", err, want)
+	}
+}
+
+func TestPositions_validationError(t *testing.T) {
+	schema, err := ParseSchema([]byte("{\n  \"minLength\": 2\n}"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Validate(schema, []byte(`"a"`))
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Causes) != 1 {
+		t.Fatalf("got error %v, want 1 validation error", err)
+	}
+	if got, want := verr.Causes[0].SchemaPosition.String(), "2:16"; got != want {
+		t.Errorf("got position %s, want %s", got, want)
+	}
+}
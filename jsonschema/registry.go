@@ -0,0 +1,339 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// A Registry indexes JSON Schemas (and all of their subschemas) by the URIs that identify them, so
+// that "$ref" values can be resolved.
+//
+// A subschema is identified by its own "$id" (if any) and by a JSON Pointer fragment relative to
+// each enclosing schema resource (the root schema and each ancestor with an "$id"). See
+// https://tools.ietf.org/html/draft-handrews-json-schema-01#section-8.2.
+//
+// A Registry is safe for concurrent use by multiple goroutines.
+type Registry struct {
+	mu sync.Mutex
+
+	byURI    map[string]*Schema // keyed by uriKey
+	ids      map[*Schema]ID     // the canonical ID of each (sub)schema (relative to its nearest schema resource)
+	dialects map[*Schema]dialect
+
+	defaultDialect dialect            // the dialect of documents without "$schema"
+	metaSchemas    map[string]dialect // the dialects of custom meta-schemas, keyed by URI
+
+	loader Loader          // loads documents referred to by unresolvable $refs (if non-nil)
+	loaded map[string]bool // URIs of documents that the loader was called for
+}
+
+// NewRegistry returns a new, empty registry. If loader is non-nil, it is used to load documents that
+// are referred to but are not in the registry.
+func NewRegistry(loader Loader) *Registry {
+	return &Registry{
+		byURI:          map[string]*Schema{},
+		ids:            map[*Schema]ID{},
+		dialects:       map[*Schema]dialect{},
+		defaultDialect: defaultDialect,
+		metaSchemas:    map[string]dialect{},
+		loader:         loader,
+		loaded:         map[string]bool{},
+	}
+}
+
+// Add adds the schema document and all of its subschemas to the registry.
+//
+// The uri is the URI that the document was retrieved from, which is used as the document's initial
+// base URI. If uri is nil, the document has no base URI (other than its own "$id", if any); in
+// that case, fragment-only references in the document (such as "#/definitions/a") only resolve
+// correctly if no other document without a base URI is added to the registry.
+func (r *Registry) Add(schema *Schema, uri *url.URL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.add(schema, uri, nil, r.defaultDialect)
+}
+
+// Resolve returns the schema that the reference (a "$ref" value) refers to, resolving it against
+// the base ID (usually the ID of the schema that contains the "$ref"; see the ID method). If the
+// reference refers to a document that is not in the registry, the document is loaded (and added to
+// the registry) using the registry's loader.
+func (r *Registry) Resolve(base ID, ref string) (*Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.resolveRef(base.URI(), ref)
+}
+
+// Lookup returns the schema in the registry that is identified by the URI, or nil if there is
+// none.
+func (r *Registry) Lookup(uri *url.URL) *Schema {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.byURI[uriKey(uri)]
+}
+
+// ID returns the canonical ID of the schema, which must have been added to the registry (either
+// directly or as a subschema of another schema). The ID's base URI is the URI of the nearest
+// enclosing schema resource.
+func (r *Registry) ID(schema *Schema) (id ID, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, ok = r.ids[schema]
+	return id, ok
+}
+
+// Draft returns the draft that applies to the schema, which must have been added to the registry
+// (either directly or as a subschema of another schema). It is the draft selected by the "$schema"
+// of the schema or of its nearest ancestor that has one (or DefaultDraft if none does).
+func (r *Registry) Draft(schema *Schema) (draft Draft, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.dialects[schema]
+	return d.draft, ok
+}
+
+// add indexes schema and all of its subschemas. The schema is located at the reference tokens rel
+// in the schema resource identified by the base URI, which is also used to resolve relative "$id"
+// values. The base URI may be nil. The schema uses dialect d unless it specifies its own "$schema".
+func (r *Registry) add(schema *Schema, base *url.URL, rel []ReferenceToken, d dialect) error {
+	var err error
+	v := registryVisitor{registry: r, err: &err, dialect: d}
+	if base != nil {
+		v.base = *base
+		v.base.Fragment = ""
+	}
+	v.scopes = []registryScope{{uri: v.base, tokens: []ReferenceToken{}}}
+	walk(&v, schema, rel)
+	return err
+}
+
+// resolve returns the schema that the "$ref" value of schema (which must be in the registry)
+// refers to.
+func (r *Registry) resolve(schema *Schema) (*Schema, error) {
+	target, err := r.resolveRef(r.ids[schema].Base, *schema.Reference)
+	if err != nil {
+		return nil, ErrorAt(schema.KeywordPos["$ref"], err)
+	}
+	return target, nil
+}
+
+func (r *Registry) resolveRef(base *url.URL, refStr string) (*Schema, error) {
+	ref, err := url.Parse(refStr)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse $ref")
+	}
+
+	// Dereference the $ref against the current base URI
+	// (https://tools.ietf.org/html/draft-handrews-json-schema-01#section-8.3.2).
+	if base != nil && *base != (url.URL{}) {
+		ref = base.ResolveReference(ref)
+	}
+
+	if target, ok := r.byURI[uriKey(ref)]; ok {
+		return target, nil
+	}
+
+	doc := *ref
+	doc.Fragment = ""
+	doc.RawFragment = ""
+	if _, ok := r.byURI[uriKey(&doc)]; !ok && r.loader != nil && doc.IsAbs() && !r.loaded[doc.String()] {
+		r.loaded[doc.String()] = true
+		root, err := r.loader.Load(&doc)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to load schema %q for $ref %q", doc.String(), refStr))
+		}
+		if err := r.add(root, &doc, nil, r.defaultDialect); err != nil {
+			return nil, err
+		}
+		if target, ok := r.byURI[uriKey(ref)]; ok {
+			return target, nil
+		}
+	}
+
+	// The $ref might point to a location in the document that is not a known subschema (such as a
+	// value under a non-keyword property).
+	if target, err := r.resolveRaw(&doc, ref.Fragment); err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to resolve $ref %q", refStr))
+	} else if target != nil {
+		return target, nil
+	}
+
+	return nil, fmt.Errorf("failed to resolve $ref: %q (dereferenced to %q)", refStr, ref)
+}
+
+// resolveRaw returns the schema at the JSON Pointer in the raw JSON of the schema resource
+// identified by uri, and it adds the schema to the registry. If there is no such schema, it returns
+// nil.
+func (r *Registry) resolveRaw(uri *url.URL, pointer string) (*Schema, error) {
+	resource := r.byURI[uriKey(uri)]
+	if resource == nil || resource.Raw == nil || !strings.HasPrefix(pointer, "/") {
+		return nil, nil
+	}
+
+	parts, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	raw := *resource.Raw
+	rel := make([]ReferenceToken, len(parts))
+	for i, name := range parts {
+		rel[i] = ReferenceToken{Name: name}
+		switch {
+		case len(raw) > 0 && raw[0] == '{':
+			var o map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &o); err != nil {
+				return nil, err
+			}
+			var ok bool
+			if raw, ok = o[name]; !ok {
+				return nil, nil
+			}
+		case len(raw) > 0 && raw[0] == '[':
+			var a []json.RawMessage
+			if err := json.Unmarshal(raw, &a); err != nil {
+				return nil, err
+			}
+			index, err := strconv.Atoi(name)
+			if err != nil || index < 0 || index >= len(a) {
+				return nil, nil
+			}
+			raw = a[index]
+		default:
+			return nil, nil
+		}
+	}
+
+	var schema *Schema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, err
+	}
+	if resource.Pos.IsValid() {
+		recordPositions(schema, *resource.Raw, pointer, resource.Pos)
+	}
+	if err := r.add(schema, uri, rel, r.dialects[resource]); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// uriKey returns the key in Registry.byURI for the URI. The fragment is compared in its
+// unescaped form.
+func uriKey(u *url.URL) string {
+	tmp := *u
+	tmp.Fragment = ""
+	tmp.RawFragment = ""
+	return tmp.String() + "#" + u.Fragment
+}
+
+// registryScope is a schema resource that encloses the (sub)schema being visited.
+type registryScope struct {
+	uri    url.URL          // the URI of the resource (with no fragment)
+	tokens []ReferenceToken // the location of the current (sub)schema relative to the resource's root
+}
+
+// registryVisitor implements Visitor.
+type registryVisitor struct {
+	registry *Registry
+	err      *error
+
+	base    url.URL
+	scopes  []registryScope
+	dialect dialect
+}
+
+// Visit implements Visitor.
+func (v *registryVisitor) Visit(schema *Schema, rel []ReferenceToken) Visitor {
+	if schema == nil || *v.err != nil {
+		return nil
+	}
+
+	w := *v // copy
+	w.scopes = make([]registryScope, len(v.scopes))
+	for i, scope := range v.scopes {
+		w.scopes[i] = registryScope{uri: scope.uri, tokens: appendTokens(scope.tokens, rel...)}
+	}
+
+	if schema.SchemaRef != nil {
+		w.dialect = w.registry.dialectFor(*schema.SchemaRef, v.dialect)
+	}
+
+	// In draft-07, siblings of "$ref" (including "$id") are ignored
+	// (https://tools.ietf.org/html/draft-handrews-json-schema-01#section-8.3).
+	if schema.ID != nil && (schema.Reference == nil || w.dialect.draft >= Draft201909) {
+		id, err := url.Parse(*schema.ID)
+		if err != nil {
+			*v.err = errors.WithMessage(err, "failed to parse $id")
+			return nil
+		}
+		id = v.base.ResolveReference(id)
+		if id.Fragment == "" || strings.HasPrefix(id.Fragment, "/") {
+			// The "$id" establishes a new schema resource (and base URI).
+			id.Fragment = ""
+			if *id != w.base || len(rel) > 0 {
+				w.scopes = append(w.scopes, registryScope{uri: *id, tokens: []ReferenceToken{}})
+			}
+			w.base = *id
+		} else {
+			// The "$id" is a plain-name fragment (a location-independent identifier).
+			w.registry.byURI[uriKey(id)] = schema
+		}
+	}
+
+	// In 2019-09 and later, plain-name fragments are defined by "$anchor" (and, in 2020-12,
+	// "$dynamicAnchor").
+	if w.dialect.draft >= Draft201909 {
+		for _, anchor := range []*string{schema.Anchor, schema.DynamicAnchor} {
+			if anchor != nil {
+				u := w.base
+				u.Fragment = *anchor
+				w.registry.byURI[uriKey(&u)] = schema
+			}
+		}
+	}
+
+	for _, scope := range w.scopes {
+		u := scope.uri
+		u.Fragment = encodeJSONPointer(scope.tokens)
+		w.registry.byURI[uriKey(&u)] = schema
+	}
+	innermost := w.scopes[len(w.scopes)-1]
+	w.registry.ids[schema] = ID{Base: &innermost.uri, ReferenceTokens: innermost.tokens}
+	w.registry.dialects[schema] = w.dialect
+	return &w
+}
+
+// dialectFor returns the dialect selected by a "$schema" value. If it does not identify a known
+// draft, the custom meta-schema is loaded (if possible) to determine the dialect; otherwise the
+// inherited dialect is used.
+func (r *Registry) dialectFor(metaSchemaURI string, inherited dialect) dialect {
+	if draft, ok := DraftForMetaSchemaURI(metaSchemaURI); ok {
+		return dialect{draft: draft, validation: true}
+	}
+	if d, ok := r.metaSchemas[metaSchemaURI]; ok {
+		return d
+	}
+	d := inherited
+	if u, err := url.Parse(metaSchemaURI); err == nil && u.IsAbs() && r.loader != nil {
+		u.Fragment = ""
+		u.RawFragment = ""
+		if metaSchema, err := r.loader.Load(u); err == nil && metaSchema != nil {
+			d = dialectForMetaSchema(metaSchema)
+		}
+	}
+	r.metaSchemas[metaSchemaURI] = d
+	return d
+}
+
+// appendTokens returns a new slice consisting of the reference tokens in base followed by tokens.
+// Unlike append, it never modifies the underlying array of base.
+func appendTokens(base []ReferenceToken, tokens ...ReferenceToken) []ReferenceToken {
+	tmp := make([]ReferenceToken, len(base)+len(tokens))
+	copy(tmp, base)
+	copy(tmp[len(base):], tokens)
+	return tmp
+}
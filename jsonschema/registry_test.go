@@ -0,0 +1,96 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	parse := func(data string) *Schema {
+		var schema *Schema
+		if err := json.Unmarshal([]byte(data), &schema); err != nil {
+			t.Fatal(err)
+		}
+		return schema
+	}
+
+	root := parse(`{
+  "$id": "http://example.com/root.json",
+  "definitions": {
+    "a": {"type": "string"},
+    "b": {"$id": "b.json", "definitions": {"c": {"type": "integer"}}},
+    "d": {"$id": "#d", "type": "boolean"}
+  }
+}`)
+	loaded := parse(`{"definitions": {"e": {"type": "null"}}}`)
+	registry := NewRegistry(LoaderFunc(func(uri *url.URL) (*Schema, error) {
+		if uri.String() != "http://example.com/other.json" {
+			t.Errorf("unexpected load of %q", uri)
+		}
+		return loaded, nil
+	}))
+	if err := registry.Add(root, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	defs := *root.Definitions
+	a, b, d := defs["a"], defs["b"], defs["d"]
+	c, e := (*b.Definitions)["c"], (*loaded.Definitions)["e"]
+
+	rootID, ok := registry.ID(root)
+	if !ok {
+		t.Fatal("root schema has no ID")
+	}
+	tests := map[string]struct {
+		base ID
+		ref  string
+		want *Schema
+	}{
+		"pointer":               {base: rootID, ref: "#/definitions/a", want: a},
+		"absolute URI":          {ref: "http://example.com/root.json#/definitions/a", want: a},
+		"plain-name fragment":   {base: rootID, ref: "#d", want: d},
+		"embedded resource":     {base: rootID, ref: "b.json", want: b},
+		"pointer in embedded":   {base: rootID, ref: "b.json#/definitions/c", want: c},
+		"pointer through $id":   {base: rootID, ref: "#/definitions/b/definitions/c", want: c},
+		"loaded document":       {base: rootID, ref: "other.json", want: loaded},
+		"pointer in loaded":     {base: rootID, ref: "other.json#/definitions/e", want: e},
+		"relative to nested ID": {base: ID{Base: mustParseURL(t, "http://example.com/b.json")}, ref: "#/definitions/c", want: c},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := registry.Resolve(test.base, test.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("unresolvable", func(t *testing.T) {
+		if _, err := registry.Resolve(rootID, "#/definitions/x"); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		if got, want := registry.Lookup(mustParseURL(t, "http://example.com/b.json#/definitions/c")), c; got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+		if got := registry.Lookup(mustParseURL(t, "http://example.com/none.json")); got != nil {
+			t.Errorf("got %+v, want nil", got)
+		}
+	})
+
+	t.Run("ID", func(t *testing.T) {
+		id, ok := registry.ID(c)
+		if !ok {
+			t.Fatal("no ID")
+		}
+		if got, want := id.String(), "http://example.com/b.json#/definitions/c"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// A RelativePointer is a Relative JSON Pointer (see
+// [draft-handrews-relative-json-pointer-01](https://tools.ietf.org/html/draft-handrews-relative-json-pointer-01)),
+// such as "1/a" or "2#". It refers to a value relative to a location in a JSON document.
+type RelativePointer struct {
+	Up      int     // the number of levels up from the current location (the non-negative integer prefix)
+	Pointer Pointer // the JSON Pointer to evaluate from there (if !Key)
+	Key     bool    // "#": refers to the member name or array index of the location, not its value
+}
+
+// ParseRelativePointer parses a Relative JSON Pointer.
+func ParseRelativePointer(s string) (RelativePointer, error) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 || (i > 1 && s[0] == '0') {
+		return RelativePointer{}, fmt.Errorf("invalid relative JSON Pointer %q (must begin with a non-negative integer)", s)
+	}
+	up, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return RelativePointer{}, fmt.Errorf("invalid relative JSON Pointer %q: %s", s, err)
+	}
+	if s[i:] == "#" {
+		return RelativePointer{Up: up, Key: true}, nil
+	}
+	p, err := ParseJSONPointer(s[i:])
+	if err != nil {
+		return RelativePointer{}, fmt.Errorf("invalid relative JSON Pointer %q: %s", s, err)
+	}
+	return RelativePointer{Up: up, Pointer: p}, nil
+}
+
+func (p RelativePointer) String() string {
+	s := strconv.Itoa(p.Up)
+	if p.Key {
+		return s + "#"
+	}
+	return s + p.Pointer.String()
+}
+
+// Resolve returns the (absolute) JSON Pointer to the value that p refers to when evaluated at the
+// location (an absolute JSON Pointer). If p.Key is true, it returns the pointer to the value whose
+// member name or array index p refers to.
+func (p RelativePointer) Resolve(location Pointer) (Pointer, error) {
+	if p.Up > len(location) {
+		return nil, fmt.Errorf("relative JSON Pointer %q: cannot go up %d levels from %q", p, p.Up, location)
+	}
+	base := location[:len(location)-p.Up]
+	if p.Key {
+		if len(base) == 0 {
+			return nil, fmt.Errorf("relative JSON Pointer %q: the root value at %q has no member name or array index", p, location)
+		}
+		return append(Pointer{}, base...), nil
+	}
+	abs := make(Pointer, 0, len(base)+len(p.Pointer))
+	abs = append(abs, base...)
+	return append(abs, p.Pointer...), nil
+}
+
+// Get evaluates p at the location (an absolute JSON Pointer) in the decoded JSON document. If
+// p.Key is true, it returns the member name (a string) or array index (an int) of the value that p
+// refers to; otherwise it returns the value.
+func (p RelativePointer) Get(doc interface{}, location Pointer) (interface{}, error) {
+	abs, err := p.Resolve(location)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Key {
+		return abs.Get(doc)
+	}
+
+	// Check that the value exists and determine whether its parent is an object or an array.
+	parent, err := abs[:len(abs)-1].Get(doc)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := abs.Get(doc); err != nil {
+		return nil, err
+	}
+	token := abs[len(abs)-1]
+	if _, ok := parent.([]interface{}); ok {
+		return strconv.Atoi(token)
+	}
+	return token, nil
+}
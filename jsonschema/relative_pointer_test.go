@@ -0,0 +1,71 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRelativePointer(t *testing.T) {
+	// Examples from
+	// https://tools.ietf.org/html/draft-handrews-relative-json-pointer-01#section-5.1.
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{"foo": ["bar", "baz"], "highly": {"nested": {"objects": true}}}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		location string
+		pointer  string
+		want     interface{}
+	}{
+		{location: "/foo/1", pointer: "0", want: "baz"},
+		{location: "/foo/1", pointer: "1/0", want: "bar"},
+		{location: "/foo/1", pointer: "2/highly/nested/objects", want: true},
+		{location: "/foo/1", pointer: "0#", want: 1},
+		{location: "/foo/1", pointer: "1#", want: "foo"},
+		{location: "/highly/nested", pointer: "0/objects", want: true},
+		{location: "/highly/nested", pointer: "1/nested/objects", want: true},
+		{location: "/highly/nested", pointer: "2/foo/0", want: "bar"},
+		{location: "/highly/nested", pointer: "0#", want: "nested"},
+		{location: "/highly/nested", pointer: "1#", want: "highly"},
+	}
+	for _, test := range tests {
+		t.Run(test.location+" "+test.pointer, func(t *testing.T) {
+			p, err := ParseRelativePointer(test.pointer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.String(); got != test.pointer {
+				t.Errorf("String: got %q, want %q", got, test.pointer)
+			}
+			location, err := ParseJSONPointer(test.location)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Get(doc, location)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %#v, want %#v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for _, s := range []string{"", "#", "01", "-1", "1a", "0/a~2"} {
+			if _, err := ParseRelativePointer(s); err == nil {
+				t.Errorf("%q: got nil error, want error", s)
+			}
+		}
+		for _, s := range []string{"3", "2#"} {
+			p, err := ParseRelativePointer(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := p.Get(doc, Pointer{"foo", "1"}); err == nil {
+				t.Errorf("%q: got nil error, want error", s)
+			}
+		}
+	})
+}
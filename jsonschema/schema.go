@@ -3,64 +3,135 @@ package jsonschema
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 )
 
-// Schema is a JSON Schema draft-07 document (as specified in
-// [draft-handrews-json-schema-01](https://tools.ietf.org/html/draft-handrews-json-schema-01)).
+// Schema is a JSON Schema document (as specified in
+// [draft-handrews-json-schema-01](https://tools.ietf.org/html/draft-handrews-json-schema-01) for
+// draft-07, and in the [2019-09](https://json-schema.org/specification-links.html#2019-09-formerly-known-as-draft-8)
+// and [2020-12](https://json-schema.org/specification-links.html#2020-12) specifications). The
+// keywords introduced in 2019-09 and 2020-12 are only meaningful in schemas of those drafts (see
+// the Draft method).
+//
+// Const and Default are nil if the keyword is absent; a JSON null value is represented by a non-nil
+// pointer to a nil interface{} value. Likewise, Enum (and each of the other fields whose value is a
+// JSON array, such as Required) is nil if the keyword is absent and non-nil (but empty) if it is an
+// empty array.
+//
+// A schema that was unmarshaled from JSON is marshaled to the same JSON (apart from insignificant
+// whitespace and the escaping that encoding/json applies to strings), with its properties at every
+// level in the same order and with the same null values and spelling of numbers (such as 1.0).
+// Only the values of the keywords that are modified after unmarshaling are encoded anew, and
+// properties that are added are encoded after those from the source document.
 type Schema struct {
-	Comment              *string                      `json:"$comment,omitempty"`
-	ID                   *string                      `json:"$id,omitempty"`
-	Reference            *string                      `json:"$ref,omitempty"`
-	SchemaRef            *string                      `json:"$schema,omitempty"`
-	AdditionalItems      *Schema                      `json:"additionalItems,omitempty"`
-	AdditionalProperties *Schema                      `json:"additionalProperties,omitempty"`
-	AllOf                []*Schema                    `json:"allOf,omitempty"`
-	AnyOf                []*Schema                    `json:"anyOf,omitempty"`
-	Const                *interface{}                 `json:"const,omitempty"`
-	Contains             *Schema                      `json:"contains,omitempty"`
-	Default              *interface{}                 `json:"default,omitempty"`
-	Definitions          *map[string]*Schema          `json:"definitions,omitempty"`
-	Dependencies         *map[string]*DependencyValue `json:"dependencies,omitempty"`
-	Description          *string                      `json:"description,omitempty"`
-	Else                 *Schema                      `json:"else,omitempty"`
-	Enum                 EnumList                     `json:"enum,omitempty"`
-	Examples             []interface{}                `json:"examples,omitempty"`
-	ExclusiveMaximum     *float64                     `json:"exclusiveMaximum,omitempty"`
-	ExclusiveMinimum     *float64                     `json:"exclusiveMinimum,omitempty"`
-	Format               *Format                      `json:"format,omitempty"`
-	If                   *Schema                      `json:"if,omitempty"`
-	Items                *SchemaOrSchemaList          `json:"items,omitempty"`
-	MaxItems             *int64                       `json:"maxItems,omitempty"`
-	MaxLength            *int64                       `json:"maxLength,omitempty"`
-	MaxProperties        *int64                       `json:"maxProperties,omitempty"`
-	Maximum              *float64                     `json:"maximum,omitempty"`
-	MinItems             *int64                       `json:"minItems,omitempty"`
-	MinLength            *int64                       `json:"minLength,omitempty"`
-	MinProperties        *int64                       `json:"minProperties,omitempty"`
-	Minimum              *float64                     `json:"minimum,omitempty"`
-	MultipleOf           *float64                     `json:"multipleOf,omitempty"`
-	Not                  *Schema                      `json:"not,omitempty"`
-	OneOf                []*Schema                    `json:"oneOf,omitempty"`
-	Pattern              *string                      `json:"pattern,omitempty"`
-	PatternProperties    *map[string]*Schema          `json:"patternProperties,omitempty"`
-	Properties           *map[string]*Schema          `json:"properties,omitempty"`
-	PropertyNames        *Schema                      `json:"propertyNames,omitempty"`
-	Required             []string                     `json:"required,omitempty"`
-	Then                 *Schema                      `json:"then,omitempty"`
-	Title                *string                      `json:"title,omitempty"`
-	Type                 PrimitiveTypeList            `json:"type,omitempty"`
-	UniqueItems          *bool                        `json:"uniqueItems,omitempty"`
+	Anchor                *string                      `json:"$anchor,omitempty"`
+	Comment               *string                      `json:"$comment,omitempty"`
+	Defs                  *map[string]*Schema          `json:"$defs,omitempty"`
+	DynamicAnchor         *string                      `json:"$dynamicAnchor,omitempty"`
+	DynamicRef            *string                      `json:"$dynamicRef,omitempty"`
+	ID                    *string                      `json:"$id,omitempty"`
+	RecursiveAnchor       *bool                        `json:"$recursiveAnchor,omitempty"`
+	RecursiveRef          *string                      `json:"$recursiveRef,omitempty"`
+	Reference             *string                      `json:"$ref,omitempty"`
+	SchemaRef             *string                      `json:"$schema,omitempty"`
+	Vocabulary            *map[string]bool             `json:"$vocabulary,omitempty"`
+	AdditionalItems       *Schema                      `json:"additionalItems,omitempty"`
+	AdditionalProperties  *Schema                      `json:"additionalProperties,omitempty"`
+	AllOf                 []*Schema                    `json:"allOf,omitempty"`
+	AnyOf                 []*Schema                    `json:"anyOf,omitempty"`
+	Const                 *interface{}                 `json:"const,omitempty"`
+	Contains              *Schema                      `json:"contains,omitempty"`
+	ContentEncoding       *string                      `json:"contentEncoding,omitempty"`
+	ContentMediaType      *string                      `json:"contentMediaType,omitempty"`
+	Default               *interface{}                 `json:"default,omitempty"`
+	Definitions           *map[string]*Schema          `json:"definitions,omitempty"`
+	Dependencies          *map[string]*DependencyValue `json:"dependencies,omitempty"`
+	DependentRequired     *map[string][]string         `json:"dependentRequired,omitempty"`
+	DependentSchemas      *map[string]*Schema          `json:"dependentSchemas,omitempty"`
+	Description           *string                      `json:"description,omitempty"`
+	Else                  *Schema                      `json:"else,omitempty"`
+	Enum                  EnumList                     `json:"enum,omitempty"`
+	Examples              []interface{}                `json:"examples,omitempty"`
+	ExclusiveMaximum      *float64                     `json:"exclusiveMaximum,omitempty"`
+	ExclusiveMinimum      *float64                     `json:"exclusiveMinimum,omitempty"`
+	Format                *Format                      `json:"format,omitempty"`
+	If                    *Schema                      `json:"if,omitempty"`
+	Items                 *SchemaOrSchemaList          `json:"items,omitempty"`
+	MaxContains           *int64                       `json:"maxContains,omitempty"`
+	MaxItems              *int64                       `json:"maxItems,omitempty"`
+	MaxLength             *int64                       `json:"maxLength,omitempty"`
+	MaxProperties         *int64                       `json:"maxProperties,omitempty"`
+	Maximum               *float64                     `json:"maximum,omitempty"`
+	MinContains           *int64                       `json:"minContains,omitempty"`
+	MinItems              *int64                       `json:"minItems,omitempty"`
+	MinLength             *int64                       `json:"minLength,omitempty"`
+	MinProperties         *int64                       `json:"minProperties,omitempty"`
+	Minimum               *float64                     `json:"minimum,omitempty"`
+	MultipleOf            *float64                     `json:"multipleOf,omitempty"`
+	Not                   *Schema                      `json:"not,omitempty"`
+	OneOf                 []*Schema                    `json:"oneOf,omitempty"`
+	Pattern               *string                      `json:"pattern,omitempty"`
+	PatternProperties     *map[string]*Schema          `json:"patternProperties,omitempty"`
+	PrefixItems           []*Schema                    `json:"prefixItems,omitempty"`
+	Properties            *map[string]*Schema          `json:"properties,omitempty"`
+	PropertyNames         *Schema                      `json:"propertyNames,omitempty"`
+	ReadOnly              *bool                        `json:"readOnly,omitempty"`
+	Required              []string                     `json:"required,omitempty"`
+	Then                  *Schema                      `json:"then,omitempty"`
+	Title                 *string                      `json:"title,omitempty"`
+	Type                  PrimitiveTypeList            `json:"type,omitempty"`
+	UnevaluatedItems      *Schema                      `json:"unevaluatedItems,omitempty"`
+	UnevaluatedProperties *Schema                      `json:"unevaluatedProperties,omitempty"`
+	UniqueItems           *bool                        `json:"uniqueItems,omitempty"`
+	WriteOnly             *bool                        `json:"writeOnly,omitempty"`
 
 	// Raw is the raw JSON document that this schema was unmarshaled from, if any. It can be used to
 	// retrieve and set custom properties (such as for extensions to JSON Schema). It is omitted
 	// from the JSON encoding of this value.
 	Raw *json.RawMessage `json:"-"`
 
+	// Pos is the position of the schema in the source of the document it was parsed from, and
+	// KeywordPos is the position of the value of each of its keywords (and other properties).
+	// They are recorded by ParseSchema (which loaders use), but not by json.Unmarshal, and not for
+	// draft-04 and draft-06 documents (which are upgraded when they are unmarshaled).
+	Pos        Position            `json:"-"`
+	KeywordPos map[string]Position `json:"-"`
+
+	// KeyOrder maps each keyword whose value is an object of schemas (such as "properties") to the
+	// property names of that object in the order in which they appear in the source document. Use
+	// OrderedKeys to get the keys of such a keyword in document order.
+	KeyOrder map[string][]string `json:"-"`
+
+	// Extensions holds the parsed values of the schema's custom keywords (see RegisterKeyword),
+	// keyed by keyword. They are included in the JSON encoding of this value.
+	Extensions map[string]interface{} `json:"-"`
+
+	// Unknown holds the raw values of the schema's other properties, which are neither keywords
+	// nor registered custom keywords (such as subschemas in non-standard locations that "$ref"s
+	// refer to), keyed by name. They are included in the JSON encoding of this value.
+	Unknown map[string]json.RawMessage `json:"-"`
+
+	// memberOrder is the order of the properties in the source document, if it differs from the
+	// order in which they are encoded (see recordMemberOrder). nullKeywords are the keywords whose
+	// values are null in the source document (other than const and default), which the fields
+	// can't represent. sourceValues holds the source JSON of the keyword values whose encoding
+	// differs from it (see recordSourceValues).
+	memberOrder  []string
+	nullKeywords []string
+	sourceValues map[string]sourceValue
+
 	IsEmpty   bool `json:"-"` // the schema is "true"
 	IsNegated bool `json:"-"` // the schema is "false"
 
+	// TypeIsList records that the "type" value is an array of types (and not a single type). Types
+	// are marshaled as a single type if there is only one type and TypeIsList is false.
+	TypeIsList bool `json:"-"`
+
 	// Go contains Go-specific extensions that JSON Schema authors can specify.
 	Go *struct {
 		TaggedUnionType bool `json:"taggedUnionType,omitempty"`
@@ -68,6 +139,385 @@ type Schema struct {
 	} `json:"!go,omitempty"`
 }
 
+// schemaMapKeywords are the keywords whose values are objects of schemas.
+var schemaMapKeywords = []string{"$defs", "definitions", "dependencies", "dependentSchemas", "patternProperties", "properties"}
+
+// schemaMap returns the object of schemas that is the value of the keyword (one of
+// schemaMapKeywords), or nil if there is none.
+func (s *Schema) schemaMap(keyword string) map[string]*Schema {
+	var m *map[string]*Schema
+	switch keyword {
+	case "$defs":
+		m = s.Defs
+	case "definitions":
+		m = s.Definitions
+	case "dependencies":
+		if s.Dependencies == nil {
+			return nil
+		}
+		deps := make(map[string]*Schema, len(*s.Dependencies))
+		for name, dep := range *s.Dependencies {
+			if dep != nil {
+				deps[name] = dep.Schema
+			} else {
+				deps[name] = nil
+			}
+		}
+		return deps
+	case "dependentSchemas":
+		m = s.DependentSchemas
+	case "patternProperties":
+		m = s.PatternProperties
+	case "properties":
+		m = s.Properties
+	}
+	if m == nil {
+		return nil
+	}
+	return *m
+}
+
+// OrderedKeys returns the property names of the object of schemas that is the value of the keyword
+// (such as "properties", "patternProperties", "definitions" or "$defs"), in the order in which they
+// appear in the source document (see KeyOrder). Property names that are not in KeyOrder (such as
+// those added after the schema was unmarshaled) follow, sorted by name.
+func (s *Schema) OrderedKeys(keyword string) []string {
+	m := s.schemaMap(keyword)
+	keys := make([]string, 0, len(m))
+	seen := make(map[string]bool, len(m))
+	for _, key := range s.KeyOrder[keyword] {
+		if _, ok := m[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	rest := len(keys)
+	for key := range m {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys[rest:])
+	return keys
+}
+
+// applyKeyOrder reorders the properties of the values of the keywords in KeyOrder in the schema's
+// JSON encoding (in which they are sorted by name), so that the document order is preserved.
+func (s *Schema) applyKeyOrder(data []byte) ([]byte, error) {
+	var reorder bool
+	for _, keyword := range schemaMapKeywords {
+		if keys := s.KeyOrder[keyword]; !sort.StringsAreSorted(keys) {
+			reorder = true
+		}
+	}
+	if !reorder {
+		return data, nil
+	}
+
+	members, err := scanObjectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range members {
+		if _, ok := s.KeyOrder[m.key]; !ok || s.schemaMap(m.key) == nil {
+			continue
+		}
+		props, err := scanObjectMembers(m.value)
+		if err != nil {
+			return nil, err
+		}
+		index := make(map[string]int, len(props))
+		for j, prop := range props {
+			index[prop.key] = j
+		}
+		ordered := make([]rawMember, 0, len(props))
+		for _, key := range s.OrderedKeys(m.key) {
+			if j, ok := index[key]; ok {
+				ordered = append(ordered, props[j])
+			}
+		}
+		members[i].value = formatRawObject(ordered)
+	}
+	return formatRawObject(members), nil
+}
+
+// applyValueForms rewrites the values in the schema's JSON encoding whose form differs from the
+// form in the source document: a "type" list of 1 type (see TypeIsList), which is encoded as a
+// single type, and empty lists (such as "required": []), which are omitted.
+func (s *Schema) applyValueForms(data []byte) ([]byte, error) {
+	typeList := s.TypeIsList && len(s.Type) == 1
+	var empty []string
+	for _, list := range []struct {
+		keyword string
+		empty   bool
+	}{
+		{keyword: "allOf", empty: s.AllOf != nil && len(s.AllOf) == 0},
+		{keyword: "anyOf", empty: s.AnyOf != nil && len(s.AnyOf) == 0},
+		{keyword: "enum", empty: s.Enum != nil && len(s.Enum) == 0},
+		{keyword: "examples", empty: s.Examples != nil && len(s.Examples) == 0},
+		{keyword: "oneOf", empty: s.OneOf != nil && len(s.OneOf) == 0},
+		{keyword: "prefixItems", empty: s.PrefixItems != nil && len(s.PrefixItems) == 0},
+		{keyword: "required", empty: s.Required != nil && len(s.Required) == 0},
+		{keyword: "type", empty: s.TypeIsList && len(s.Type) == 0},
+	} {
+		if list.empty {
+			empty = append(empty, list.keyword)
+		}
+	}
+	if !typeList && len(empty) == 0 {
+		return data, nil
+	}
+	members, err := scanObjectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	if typeList {
+		for i, m := range members {
+			if m.key == "type" {
+				if members[i].value, err = json.Marshal([]PrimitiveType(s.Type)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, keyword := range empty {
+		// Insert it in the position of its field (which is ordered by name, like the fields that
+		// precede and follow it).
+		i := 0
+		for i < len(members) && members[i].key < keyword && members[i].key != "!go" {
+			i++
+		}
+		members = append(members[:i], append([]rawMember{{key: keyword, value: json.RawMessage("[]")}}, members[i:]...)...)
+	}
+	return formatRawObject(members), nil
+}
+
+// appendUnknown appends the values in the schema's Unknown to its JSON encoding (as properties
+// ordered by name), except for those whose names are already in it.
+func (s *Schema) appendUnknown(data []byte) ([]byte, error) {
+	if len(s.Unknown) == 0 {
+		return data, nil
+	}
+	members, err := scanObjectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(members))
+	for _, m := range members {
+		present[m.key] = true
+	}
+	names := make([]string, 0, len(s.Unknown))
+	for name := range s.Unknown {
+		if !present[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		members = append(members, rawMember{key: name, value: s.Unknown[name]})
+	}
+	return formatRawObject(members), nil
+}
+
+// recordMemberOrder records the order of the members of the schema's raw JSON object, if it differs
+// from the order in which MarshalJSON encodes them, and the keywords whose values are null (which
+// are otherwise omitted from the encoding).
+func (s *Schema) recordMemberOrder(members []rawMember) {
+	s.memberOrder, s.nullKeywords = nil, nil
+	ordered := true
+	for i, m := range members {
+		if schemaKeywords[m.key] && m.key != "const" && m.key != "default" && bytes.Equal(m.value, nullBytes) {
+			s.nullKeywords = append(s.nullKeywords, m.key)
+		}
+		if i > 0 && !encodedBefore(members[i-1].key, m.key) {
+			ordered = false
+		}
+	}
+	if ordered && s.nullKeywords == nil {
+		return
+	}
+	s.memberOrder = make([]string, len(members))
+	for i, m := range members {
+		s.memberOrder[i] = m.key
+	}
+}
+
+// encodedBefore reports whether MarshalJSON encodes the property named a before the property
+// named b: keywords in the order of their fields, followed by custom keywords and then other
+// properties (each sorted by name).
+func encodedBefore(a, b string) bool {
+	rank := func(name string) int {
+		if i, ok := schemaKeywordIndex[name]; ok {
+			return i
+		} else if LookupKeyword(name) != nil {
+			return len(schemaKeywordIndex)
+		}
+		return len(schemaKeywordIndex) + 1
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra < rb
+	}
+	return a < b
+}
+
+// applyMemberOrder restores the order of the properties and the null keyword values of the source
+// document (see recordMemberOrder) in the schema's JSON encoding.
+func (s *Schema) applyMemberOrder(data []byte) ([]byte, error) {
+	if s.memberOrder == nil {
+		return data, nil
+	}
+	members, err := scanObjectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(members))
+	for i, m := range members {
+		index[m.key] = i
+	}
+	for _, keyword := range s.nullKeywords {
+		if _, ok := index[keyword]; !ok {
+			index[keyword] = len(members)
+			members = append(members, rawMember{key: keyword, value: json.RawMessage(nullBytes)})
+		}
+	}
+	ordered := make([]rawMember, 0, len(members))
+	done := make([]bool, len(members))
+	for _, key := range s.memberOrder {
+		if i, ok := index[key]; ok && !done[i] {
+			ordered = append(ordered, members[i])
+			done[i] = true
+		}
+	}
+	for i, m := range members {
+		if !done[i] {
+			ordered = append(ordered, m)
+		}
+	}
+	return formatRawObject(ordered), nil
+}
+
+// A sourceValue is the source JSON of a keyword value and the encoding of the field that holds it.
+type sourceValue struct {
+	raw, encoded json.RawMessage
+}
+
+// recordSourceValues records the source JSON of the values of the keywords (other than "type" and
+// those whose values contain subschemas, which preserve their own form) whose encoding differs
+// from it, such as numbers written as 1.0 and objects whose properties are not sorted by name.
+func (s *Schema) recordSourceValues(members []rawMember) error {
+	s.sourceValues = nil
+	fields := reflect.ValueOf(s).Elem()
+	for _, m := range members {
+		i, ok := schemaKeywordIndex[m.key]
+		if !ok || m.key == "type" || schemaValuedKeywords[m.key] {
+			continue
+		}
+		encoded, err := json.Marshal(fields.Field(i).Interface())
+		if err != nil {
+			return err
+		}
+		var raw bytes.Buffer
+		if err := json.Compact(&raw, m.value); err != nil {
+			return err
+		}
+		if !bytes.Equal(raw.Bytes(), encoded) {
+			if s.sourceValues == nil {
+				s.sourceValues = map[string]sourceValue{}
+			}
+			s.sourceValues[m.key] = sourceValue{raw: raw.Bytes(), encoded: encoded}
+		}
+	}
+	return nil
+}
+
+// applySourceValues replaces the values in the schema's JSON encoding with their source JSON (see
+// recordSourceValues), unless they were modified after the schema was unmarshaled.
+func (s *Schema) applySourceValues(data []byte) ([]byte, error) {
+	if len(s.sourceValues) == 0 {
+		return data, nil
+	}
+	members, err := scanObjectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range members {
+		if v, ok := s.sourceValues[m.key]; ok && bytes.Equal(m.value, v.encoded) {
+			members[i].value = v.raw
+		}
+	}
+	return formatRawObject(members), nil
+}
+
+// schemaValuedKeywords is the set of keywords whose values contain subschemas.
+var schemaValuedKeywords = func() map[string]bool {
+	var containsSchemas func(t reflect.Type) bool
+	containsSchemas = func(t reflect.Type) bool {
+		if t == reflect.TypeOf(Schema{}) {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			return containsSchemas(t.Elem())
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				if containsSchemas(t.Field(i).Type) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	keywords := map[string]bool{}
+	t := reflect.TypeOf(Schema{})
+	for name, i := range schemaKeywordIndex {
+		if containsSchemas(t.Field(i).Type) {
+			keywords[name] = true
+		}
+	}
+	return keywords
+}()
+
+// schemaKeywords is the set of keywords that are represented by fields of Schema, and
+// schemaKeywordIndex maps each of them to the index of its field.
+var schemaKeywords, schemaKeywordIndex = func() (map[string]bool, map[string]int) {
+	keywords, index := map[string]bool{}, map[string]int{}
+	t := reflect.TypeOf(Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			keywords[name] = true
+			index[name] = i
+		}
+	}
+	return keywords, index
+}()
+
+// presentValue returns a pointer to the value of the raw JSON, or nil if raw is nil (because the
+// value is absent). Unlike the standard JSON decoding of a pointer, a JSON null value results in a
+// non-nil pointer to a nil value.
+func presentValue(raw json.RawMessage) *interface{} {
+	if raw == nil {
+		return nil
+	}
+	var v interface{}
+	_ = json.Unmarshal(raw, &v) // raw is valid JSON
+	return &v
+}
+
+// integerValue returns the value of the raw JSON integer, which may be written with a zero
+// fractional part (such as 1.0), as JSON Schema 2019-09 and later allow. It returns nil if the
+// value is absent or null.
+func integerValue(keyword string, raw json.RawMessage) (*int64, error) {
+	if raw == nil || bytes.Equal(raw, nullBytes) {
+		return nil, nil
+	}
+	n, ok := parseNumber(string(raw))
+	if !ok || n.rat == nil || !n.rat.IsInt() || !n.rat.Num().IsInt64() {
+		return nil, fmt.Errorf("value of %q must be an integer (got %s)", keyword, raw)
+	}
+	i := n.rat.Num().Int64()
+	return &i, nil
+}
+
 // IsRequiredProperty reports whether propertyName is a required property for instances of this
 // schema.
 func (s *Schema) IsRequiredProperty(propertyName string) bool {
@@ -82,6 +532,7 @@ func (s *Schema) IsRequiredProperty(propertyName string) bool {
 
 var trueBytes = []byte("true")
 var falseBytes = []byte("false")
+var nullBytes = []byte("null")
 
 // MarshalJSON implements json.Marshaler.
 func (s *Schema) MarshalJSON() ([]byte, error) {
@@ -92,7 +543,26 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		return trueBytes, nil
 	}
 	type schema2 Schema
-	return json.Marshal((*schema2)(s))
+	data, err := json.Marshal((*schema2)(s))
+	if err != nil {
+		return nil, err
+	}
+	if data, err = s.applyKeyOrder(data); err != nil {
+		return nil, err
+	}
+	if data, err = s.applyValueForms(data); err != nil {
+		return nil, err
+	}
+	if data, err = s.appendExtensions(data); err != nil {
+		return nil, err
+	}
+	if data, err = s.appendUnknown(data); err != nil {
+		return nil, err
+	}
+	if data, err = s.applySourceValues(data); err != nil {
+		return nil, err
+	}
+	return s.applyMemberOrder(data)
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
@@ -104,19 +574,131 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 	case bytes.Equal(data, falseBytes):
 		*s = Schema{IsNegated: true, Raw: raw}
 	default:
+		// Upgrade draft-04 and draft-06 schemas to draft-07, which this type represents.
+		if needsUpgrade(data) {
+			var err error
+			if data, err = Upgrade(data, DefaultDraft); err != nil {
+				return err
+			}
+			raw = (*json.RawMessage)(&data)
+		}
 		type schema2 Schema
-		if err := json.Unmarshal(data, (*schema2)(s)); err != nil {
+		var v struct {
+			*schema2
+			Type json.RawMessage `json:"type"` // to record whether it is a single type or a list
+
+			// To distinguish null values from absent keywords.
+			Const   json.RawMessage `json:"const"`
+			Default json.RawMessage `json:"default"`
+
+			// To accept integers written with a zero fractional part (such as 1.0).
+			MaxContains   json.RawMessage `json:"maxContains"`
+			MaxItems      json.RawMessage `json:"maxItems"`
+			MaxLength     json.RawMessage `json:"maxLength"`
+			MaxProperties json.RawMessage `json:"maxProperties"`
+			MinContains   json.RawMessage `json:"minContains"`
+			MinItems      json.RawMessage `json:"minItems"`
+			MinLength     json.RawMessage `json:"minLength"`
+			MinProperties json.RawMessage `json:"minProperties"`
+		}
+		v.schema2 = (*schema2)(s)
+		if err := json.Unmarshal(data, &v); err != nil {
+			return errors.WithMessage(err, "failed to unmarshal JSON Schema")
+		}
+		s.Const, s.Default = presentValue(v.Const), presentValue(v.Default)
+		for _, k := range []struct {
+			keyword string
+			raw     json.RawMessage
+			field   **int64
+		}{
+			{"maxContains", v.MaxContains, &s.MaxContains},
+			{"maxItems", v.MaxItems, &s.MaxItems},
+			{"maxLength", v.MaxLength, &s.MaxLength},
+			{"maxProperties", v.MaxProperties, &s.MaxProperties},
+			{"minContains", v.MinContains, &s.MinContains},
+			{"minItems", v.MinItems, &s.MinItems},
+			{"minLength", v.MinLength, &s.MinLength},
+			{"minProperties", v.MinProperties, &s.MinProperties},
+		} {
+			var err error
+			if *k.field, err = integerValue(k.keyword, k.raw); err != nil {
+				return errors.WithMessage(err, "failed to unmarshal JSON Schema")
+			}
+		}
+		members, err := scanObjectMembers(data)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal JSON Schema")
+		}
+		if err := s.recordKeyOrder(members); err != nil {
+			return errors.WithMessage(err, "failed to unmarshal JSON Schema")
+		}
+		s.recordMemberOrder(members)
+		if err := s.recordSourceValues(members); err != nil {
 			return errors.WithMessage(err, "failed to unmarshal JSON Schema")
 		}
+		s.Unknown = nil
+		for _, m := range members {
+			if !schemaKeywords[m.key] && LookupKeyword(m.key) == nil {
+				if s.Unknown == nil {
+					s.Unknown = map[string]json.RawMessage{}
+				}
+				s.Unknown[m.key] = append(json.RawMessage(nil), m.value...)
+			}
+		}
+		if hasKeywords() {
+			if err := s.parseExtensions(members); err != nil {
+				return errors.WithMessage(err, "failed to unmarshal JSON Schema")
+			}
+		}
+		if v.Type != nil {
+			if err := json.Unmarshal(v.Type, &s.Type); err != nil {
+				return errors.WithMessage(err, "failed to unmarshal JSON Schema")
+			}
+			s.TypeIsList = v.Type[0] == '['
+		}
 		s.Raw = raw
 	}
 	return nil
 }
 
+// recordKeyOrder records the order of the keys of the values of the keywords in
+// schemaMapKeywords, given the members of the schema's raw JSON object (see KeyOrder).
+func (s *Schema) recordKeyOrder(members []rawMember) error {
+	s.KeyOrder = nil
+	for _, m := range members {
+		if !isSchemaMapKeyword(m.key) {
+			continue
+		}
+		values, err := scanObjectMembers(m.value)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, len(values))
+		for i, value := range values {
+			keys[i] = value.key
+		}
+		if s.KeyOrder == nil {
+			s.KeyOrder = map[string][]string{}
+		}
+		s.KeyOrder[m.key] = keys
+	}
+	return nil
+}
+
+func isSchemaMapKeyword(keyword string) bool {
+	for _, k := range schemaMapKeywords {
+		if k == keyword {
+			return true
+		}
+	}
+	return false
+}
+
 // SchemaOrSchemaList represents a value that can be either a valid JSON Schema or an array of valid
 // JSON Schemas.
 //
-// Exactly 1 field (Schema or Schemas) is set.
+// Exactly 1 field (Schema or Schemas) is set. The field that is set records the form of the value
+// in the source document, so an array of 1 schema is marshaled as an array (not a single schema).
 //
 // The ["items"
 // keyword](https://tools.ietf.org/html/draft-handrews-json-schema-validation-01#section-6.4.1) is
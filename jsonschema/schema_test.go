@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/sourcegraph/go-jsonschema/internal/testutil"
@@ -56,3 +57,168 @@ func TestRaw(t *testing.T) {
 }
 
 func strptr(s string) *string { return &s }
+
+func TestKeyOrder(t *testing.T) {
+	input := []byte(`{"properties": {"b": {}, "a": {"properties": {"y": {}, "x": {}}}}, "definitions": {"d": {}, "c": {}}}`)
+	var o *Schema
+	if err := json.Unmarshal(input, &o); err != nil {
+		t.Fatal(err)
+	}
+	(*o.Properties)["0"] = &Schema{}
+	for _, test := range []struct {
+		schema  *Schema
+		keyword string
+		want    []string
+	}{
+		{schema: o, keyword: "properties", want: []string{"b", "a", "0"}},
+		{schema: o, keyword: "definitions", want: []string{"d", "c"}},
+		{schema: (*o.Properties)["a"], keyword: "properties", want: []string{"y", "x"}},
+		{schema: o, keyword: "patternProperties", want: []string{}},
+	} {
+		if got := test.schema.OrderedKeys(test.keyword); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.keyword, got, test.want)
+		}
+	}
+
+	delete(*o.Properties, "0")
+	marshaled, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"properties":{"b":{},"a":{"properties":{"y":{},"x":{}}}},"definitions":{"d":{},"c":{}}}`; string(marshaled) != want {
+		t.Errorf("got %s, want %s", marshaled, want)
+	}
+}
+
+func TestSingularOrListForm(t *testing.T) {
+	tests := []string{
+		`{"type":"string"}`,
+		`{"type":["string"]}`,
+		`{"type":["null","string"]}`,
+		`{"items":{"type":"string"}}`,
+		`{"items":[{"type":["string"]}]}`,
+		`{"items":[]}`,
+		`{"properties":{"a":{"items":[true],"type":["array"]}}}`,
+		`{"required":[]}`,
+		`{"$comment":"c","tilde~field":{"type":"integer"},"x-note":[1]}`,
+		`{"type":"string","title":"a"}`,
+		`{"x-note":1,"$comment":"c"}`,
+		`{"enum":null}`,
+		`{"const":{"foo":"bar","baz":[1.0,2]},"maximum":3.0,"minItems":1.0}`,
+		`{"required":["b","a"],"dependentRequired":{"b":[],"a":["b"]}}`,
+		`{"title":null,"const":null,"maxLength":null,"default":null}`,
+		`{"properties":{"a":{"type":"string","title":"t","items":null}}}`,
+	}
+	for _, input := range tests {
+		var o *Schema
+		if err := json.Unmarshal([]byte(input), &o); err != nil {
+			t.Fatal(err)
+		}
+		marshaled, err := json.Marshal(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(marshaled) != input {
+			t.Errorf("got %s, want %s", marshaled, input)
+		}
+	}
+
+	t.Run("added property", func(t *testing.T) {
+		var o *Schema
+		if err := json.Unmarshal([]byte(`{"type":"string","title":null}`), &o); err != nil {
+			t.Fatal(err)
+		}
+		o.Description = strptr("d")
+		o.Title = strptr("t")
+		marshaled, err := json.Marshal(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"type":"string","title":"t","description":"d"}`; string(marshaled) != want {
+			t.Errorf("got %s, want %s", marshaled, want)
+		}
+	})
+
+	t.Run("modified value", func(t *testing.T) {
+		var o *Schema
+		if err := json.Unmarshal([]byte(`{"maximum":3.0,"minimum":1.0}`), &o); err != nil {
+			t.Fatal(err)
+		}
+		*o.Maximum = 4
+		marshaled, err := json.Marshal(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"maximum":4,"minimum":1.0}`; string(marshaled) != want {
+			t.Errorf("got %s, want %s", marshaled, want)
+		}
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		var o *Schema
+		if err := json.Unmarshal([]byte(`{"properties":{"a":{"items":[{}]},"b":{"items":{}}}}`), &o); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, pointer := range schemaPointers(o) {
+			got = append(got, pointer)
+		}
+		sort.Strings(got)
+		want := []string{"", "/properties/a", "/properties/a/items/0", "/properties/b", "/properties/b/items"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
+
+func TestNullValues(t *testing.T) {
+	tests := map[string]struct {
+		input                            string
+		wantConst, wantDefault, wantEnum bool // whether the keyword is present
+	}{
+		"absent":     {input: `{"type":"string"}`},
+		"null":       {input: `{"const":null,"default":null,"enum":[null]}`, wantConst: true, wantDefault: true, wantEnum: true},
+		"non-null":   {input: `{"const":1,"default":"a"}`, wantConst: true, wantDefault: true},
+		"empty enum": {input: `{"$id":"a","enum":[],"title":"b","!go":{"pointer":true}}`, wantEnum: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var o *Schema
+			if err := json.Unmarshal([]byte(test.input), &o); err != nil {
+				t.Fatal(err)
+			}
+			if got := o.Const != nil; got != test.wantConst {
+				t.Errorf("got const present %v, want %v", got, test.wantConst)
+			}
+			if got := o.Default != nil; got != test.wantDefault {
+				t.Errorf("got default present %v, want %v", got, test.wantDefault)
+			}
+			if got := o.Enum != nil; got != test.wantEnum {
+				t.Errorf("got enum present %v, want %v", got, test.wantEnum)
+			}
+			marshaled, err := json.Marshal(o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(marshaled) != test.input {
+				t.Errorf("got %s, want %s", marshaled, test.input)
+			}
+		})
+	}
+}
+
+func TestIntegerKeywords(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{"minItems":1.0,"maxLength":2e1,"minContains":0,"maxItems":null}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.MinItems == nil || *schema.MinItems != 1 || schema.MaxLength == nil || *schema.MaxLength != 20 || schema.MinContains == nil || *schema.MinContains != 0 || schema.MaxItems != nil {
+		t.Errorf("got minItems %v, maxLength %v, minContains %v, maxItems %v", schema.MinItems, schema.MaxLength, schema.MinContains, schema.MaxItems)
+	}
+
+	for _, input := range []string{`{"minItems":1.5}`, `{"maxLength":"1"}`, `{"minContains":1e100}`} {
+		if err := json.Unmarshal([]byte(input), &schema); err == nil {
+			t.Errorf("%s: got no error", input)
+		}
+	}
+}
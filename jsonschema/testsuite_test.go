@@ -4,21 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/sourcegraph/go-jsonschema/internal/jsonschematestsuite"
 	"github.com/sourcegraph/go-jsonschema/internal/testutil"
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
 )
 
 func TestJSONUnmarshalMarshal(t *testing.T) {
-	// TODO(sqs): Make these tests work.
-	skip := map[string]struct{}{
-		"TestJSONUnmarshalMarshal/const/const_with_null":              struct{}{},
-		"TestJSONUnmarshalMarshal/ref/escaped_pointer_ref":            struct{}{},
-		"TestJSONUnmarshalMarshal/required/required_with_empty_array": struct{}{},
-	}
-
 	files, err := jsonschematestsuite.Files("../internal")
 	if err != nil {
 		t.Fatal(err)
@@ -31,10 +26,6 @@ func TestJSONUnmarshalMarshal(t *testing.T) {
 			f.ReadT(t)
 			for _, g := range f.Groups {
 				t.Run(g.Description, func(t *testing.T) {
-					if _, ok := skip[t.Name()]; ok {
-						t.Skip()
-					}
-
 					marshaled, err := json.Marshal(g.Schema)
 					if err != nil {
 						t.Fatal(err)
@@ -50,3 +41,157 @@ func TestJSONUnmarshalMarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateTestSuite(t *testing.T) {
+	// Known gaps in conformance with the JSON Schema test suite.
+	//
+	// TODO(sqs): Make these tests work.
+	skip := map[string]struct{}{
+		"TestValidateTestSuite/definitions/valid_definition":                            struct{}{}, // needs the meta-schema
+		"TestValidateTestSuite/definitions/invalid_definition":                          struct{}{}, // needs the meta-schema
+		"TestValidateTestSuite/ref/remote_ref,_containing_refs_itself":                  struct{}{}, // needs the meta-schema
+		"TestValidateTestSuite/optional/content":                                        struct{}{}, // content assertions are not implemented
+		"TestValidateTestSuite/optional/ecmascript-regex/ECMA_262_regex_non-compliance": struct{}{}, // Go regexp syntax is used
+		"TestValidateTestSuite/optional/format/idn-hostname/validation_of_internationalized_host_names/contains_illegal_char_U+302E_Hangul_single_dot_tone_mark": struct{}{},
+	}
+	skipped := func(t *testing.T) {
+		t.Helper()
+		if _, ok := skip[t.Name()]; ok {
+			t.Skip()
+		}
+	}
+
+	files, err := jsonschematestsuite.Files("../internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		opts := jsonschema.ValidatorOptions{
+			AssertFormat: strings.HasPrefix(f.Name, filepath.Join("optional", "format")+string(os.PathSeparator)),
+			Loader:       jsonschematestsuite.RemotesLoader("../internal"),
+		}
+		t.Run(filepath.ToSlash(f.Name), func(t *testing.T) {
+			skipped(t)
+			f.ReadT(t)
+			for _, g := range f.Groups {
+				t.Run(g.Description, func(t *testing.T) {
+					skipped(t)
+					v, err := jsonschema.NewValidatorWithOptions(g.Schema, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, test := range g.Tests {
+						t.Run(test.Description, func(t *testing.T) {
+							skipped(t)
+							err := v.Validate(test.Data)
+							if valid := err == nil; valid != test.Valid {
+								t.Errorf("got valid %v (error: %v), want %v\n\nschema:   %s\ninstance: %s", valid, err, test.Valid, g.RawSchema, test.Data)
+							}
+						})
+					}
+				})
+			}
+		})
+	}
+}
+
+// TestValidateTestSuite_remotesServer checks that remote schemas can be fetched over HTTP.
+func TestValidateTestSuite_remotesServer(t *testing.T) {
+	server := jsonschematestsuite.NewRemotesServer("../internal")
+	defer server.Close()
+
+	files, err := jsonschematestsuite.Files("../internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if f.Name != "refRemote" {
+			continue
+		}
+		f.ReadT(t)
+		for _, g := range f.Groups {
+			t.Run(g.Description, func(t *testing.T) {
+				v, err := jsonschema.NewValidatorWithOptions(g.Schema, jsonschema.ValidatorOptions{
+					Loader: jsonschematestsuite.RemotesServerLoader(server),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, test := range g.Tests {
+					if err := v.Validate(test.Data); (err == nil) != test.Valid {
+						t.Errorf("%s: got error %v, want valid %v", test.Description, err, test.Valid)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestValidateTestSuite_drafts runs the official test suite for the drafts other than draft-07.
+func TestValidateTestSuite_drafts(t *testing.T) {
+	// Known gaps in conformance with the JSON Schema test suite.
+	skip := map[string]struct{}{}
+	for _, dir := range []string{"draft-04", "draft-06"} {
+		for _, name := range []string{
+			"definitions/valid_definition",           // needs the meta-schema
+			"definitions/invalid_definition",         // needs the meta-schema
+			"ref/remote_ref,_containing_refs_itself", // needs the meta-schema
+		} {
+			skip["TestValidateTestSuite_drafts/"+dir+"/"+name] = struct{}{}
+		}
+	}
+	for _, dir := range []string{"2019-09", "2020-12"} {
+		for _, name := range []string{
+			"defs/validate_definition_against_metaschema", // needs the meta-schema
+			"ref/remote_ref,_containing_refs_itself",      // needs the meta-schema
+		} {
+			skip["TestValidateTestSuite_drafts/"+dir+"/"+name] = struct{}{}
+		}
+	}
+	skipped := func(t *testing.T) {
+		t.Helper()
+		if _, ok := skip[t.Name()]; ok {
+			t.Skip()
+		}
+	}
+
+	for _, draft := range []jsonschema.Draft{jsonschema.Draft4, jsonschema.Draft6, jsonschema.Draft201909, jsonschema.Draft202012} {
+		t.Run(draft.String(), func(t *testing.T) {
+			files, err := jsonschematestsuite.DraftFiles("../internal", draft)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := jsonschema.ValidatorOptions{
+				Loader: jsonschematestsuite.RemotesLoader("../internal"),
+				Draft:  draft,
+			}
+			for _, f := range files {
+				if strings.HasPrefix(f.Name, "optional"+string(os.PathSeparator)) {
+					continue
+				}
+				t.Run(filepath.ToSlash(f.Name), func(t *testing.T) {
+					skipped(t)
+					f.ReadT(t)
+					for _, g := range f.Groups {
+						t.Run(g.Description, func(t *testing.T) {
+							skipped(t)
+							v, err := jsonschema.NewValidatorWithOptions(g.Schema, opts)
+							if err != nil {
+								t.Fatal(err)
+							}
+							for _, test := range g.Tests {
+								t.Run(test.Description, func(t *testing.T) {
+									skipped(t)
+									err := v.Validate(test.Data)
+									if valid := err == nil; valid != test.Valid {
+										t.Errorf("got valid %v (error: %v), want %v\n\nschema:   %s\ninstance: %s", valid, err, test.Valid, g.RawSchema, test.Data)
+									}
+								})
+							}
+						})
+					}
+				})
+			}
+		})
+	}
+}
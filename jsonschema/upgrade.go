@@ -0,0 +1,233 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Upgrade rewrites a raw draft-04 or draft-06 JSON Schema document to an equivalent draft-07
+// document. The document's draft is determined by its "$schema" keyword; if it has none, it is
+// assumed to be of draft defaultDraft. Documents of draft-07 and later drafts are returned
+// unchanged.
+//
+// The following changes are made (in the document and all of its subschemas):
+//
+//   - The "$schema" value is replaced with the draft-07 meta-schema URI.
+//   - A draft-04 "id" is renamed to "$id".
+//   - A draft-04 boolean "exclusiveMaximum" or "exclusiveMinimum" is replaced with the number of
+//     the corresponding "maximum" or "minimum" (if true), or removed (if false).
+//
+// The order of the properties in each object is preserved, but insignificant whitespace is not.
+//
+// Schema.UnmarshalJSON calls Upgrade automatically for documents whose "$schema" refers to the
+// draft-04 or draft-06 meta-schema.
+func Upgrade(data []byte, defaultDraft Draft) ([]byte, error) {
+	upgraded, err := upgradeSchema(data, defaultDraft)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to upgrade JSON Schema")
+	}
+	return upgraded, nil
+}
+
+// needsUpgrade reports whether the raw JSON Schema's "$schema" refers to the meta-schema of a draft
+// earlier than draft-07.
+func needsUpgrade(data []byte) bool {
+	if !bytes.Contains(data, []byte(`"$schema"`)) {
+		return false
+	}
+	var header struct {
+		SchemaRef *string `json:"$schema"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil || header.SchemaRef == nil {
+		return false
+	}
+	draft, ok := DraftForMetaSchemaURI(*header.SchemaRef)
+	return ok && draft < Draft7
+}
+
+// The keywords whose values are a schema, an object of schemas, or an array of schemas (in
+// draft-04 and draft-06).
+var (
+	upgradeSchemaKeywords      = map[string]bool{"additionalItems": true, "additionalProperties": true, "contains": true, "not": true, "propertyNames": true}
+	upgradeSchemaMapKeywords   = map[string]bool{"definitions": true, "patternProperties": true, "properties": true}
+	upgradeSchemaArrayKeywords = map[string]bool{"allOf": true, "anyOf": true, "oneOf": true}
+)
+
+// upgradeSchema upgrades the raw JSON Schema (of draft d, unless it specifies its own "$schema").
+func upgradeSchema(data []byte, d Draft) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return data, nil // a boolean schema (or an invalid schema, which is reported when unmarshaling)
+	}
+	members, err := parseRawObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]json.RawMessage, len(members))
+	for _, m := range members {
+		values[m.key] = m.value
+	}
+	var legacyMetaSchema bool // whether "$schema" refers to the meta-schema of a draft before draft-07
+	if v, ok := values["$schema"]; ok {
+		var uri string
+		if err := json.Unmarshal(v, &uri); err == nil {
+			if draft, ok := DraftForMetaSchemaURI(uri); ok {
+				d = draft
+				legacyMetaSchema = draft < Draft7
+			}
+		}
+	}
+	if d >= Draft7 {
+		return data, nil
+	}
+
+	// In draft-04, "exclusiveMaximum" and "exclusiveMinimum" are booleans that modify "maximum"
+	// and "minimum".
+	dropped := map[string]bool{}
+	exclusive := map[string]json.RawMessage{}
+	if d < Draft6 {
+		for exclusiveKeyword, limitKeyword := range map[string]string{"exclusiveMaximum": "maximum", "exclusiveMinimum": "minimum"} {
+			var b bool
+			if err := json.Unmarshal(values[exclusiveKeyword], &b); err != nil {
+				continue // absent or already a number
+			}
+			if limit, ok := values[limitKeyword]; ok && b {
+				exclusive[exclusiveKeyword] = limit
+				dropped[limitKeyword] = true
+			} else {
+				dropped[exclusiveKeyword] = true
+			}
+		}
+	}
+
+	upgraded := make([]rawMember, 0, len(members))
+	for _, m := range members {
+		if dropped[m.key] {
+			continue
+		}
+		var err error
+		switch {
+		case m.key == "$schema" && legacyMetaSchema:
+			m.value, err = json.Marshal(Draft7.MetaSchemaURI())
+		case m.key == "id" && d < Draft6:
+			if _, hasID := values["$id"]; !hasID {
+				m.key = "$id"
+			}
+		case exclusive[m.key] != nil:
+			m.value = exclusive[m.key]
+		case upgradeSchemaKeywords[m.key]:
+			m.value, err = upgradeSchema(m.value, d)
+		case upgradeSchemaMapKeywords[m.key]:
+			m.value, err = upgradeRawObject(m.value, d)
+		case upgradeSchemaArrayKeywords[m.key]:
+			m.value, err = upgradeRawArray(m.value, d)
+		case m.key == "items":
+			if bytes.HasPrefix(bytes.TrimSpace(m.value), []byte("[")) {
+				m.value, err = upgradeRawArray(m.value, d)
+			} else {
+				m.value, err = upgradeSchema(m.value, d)
+			}
+		case m.key == "dependencies":
+			// Only the values that are schemas (not arrays of property names) are upgraded.
+			m.value, err = upgradeRawObject(m.value, d)
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("in %q", m.key))
+		}
+		upgraded = append(upgraded, m)
+	}
+	return formatRawObject(upgraded), nil
+}
+
+// upgradeRawObject upgrades each property value of the raw JSON object that is a schema.
+func upgradeRawObject(data []byte, d Draft) ([]byte, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return data, nil
+	}
+	members, err := parseRawObject(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range members {
+		if members[i].value, err = upgradeSchema(m.value, d); err != nil {
+			return nil, err
+		}
+	}
+	return formatRawObject(members), nil
+}
+
+// upgradeRawArray upgrades each element of the raw JSON array of schemas.
+func upgradeRawArray(data []byte, d Draft) ([]byte, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return data, nil
+	}
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, elem := range elems {
+		upgraded, err := upgradeSchema(elem, d)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(upgraded)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// rawMember is a property of a raw JSON object.
+type rawMember struct {
+	key   string
+	value json.RawMessage
+}
+
+// parseRawObject returns the properties of the raw JSON object, in order. If data is not an
+// object, it returns no properties.
+func parseRawObject(data []byte) ([]rawMember, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil
+	}
+	var members []rawMember
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		m := rawMember{key: tok.(string)}
+		if err := dec.Decode(&m.value); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+// formatRawObject returns the raw JSON object with the properties, in order.
+func formatRawObject(members []rawMember) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUpgrade(t *testing.T) {
+	tests := map[string]struct {
+		data         string
+		defaultDraft Draft
+		want         string
+	}{
+		"draft-04 $schema": {
+			data: `{"$schema": "http://json-schema.org/draft-04/schema#", "type": "string"}`,
+			want: `{"$schema":"http://json-schema.org/draft-07/schema#","type":"string"}`,
+		},
+		"draft-06 $schema": {
+			data: `{"$schema": "http://json-schema.org/draft-06/schema#", "$id": "http://example.com/a"}`,
+			want: `{"$schema":"http://json-schema.org/draft-07/schema#","$id":"http://example.com/a"}`,
+		},
+		"id": {
+			data: `{"$schema": "http://json-schema.org/draft-04/schema#", "id": "http://example.com/a", "definitions": {"b": {"id": "#b"}}}`,
+			want: `{"$schema":"http://json-schema.org/draft-07/schema#","$id":"http://example.com/a","definitions":{"b":{"$id":"#b"}}}`,
+		},
+		"id property name": {
+			data:         `{"properties": {"id": {"type": "string"}}, "required": ["id"]}`,
+			defaultDraft: Draft4,
+			want:         `{"properties":{"id":{"type":"string"}},"required":["id"]}`,
+		},
+		"exclusive bounds": {
+			data:         `{"minimum": 1, "exclusiveMinimum": true, "maximum": 5, "exclusiveMaximum": false}`,
+			defaultDraft: Draft4,
+			want:         `{"exclusiveMinimum":1,"maximum":5}`,
+		},
+		"nested subschemas": {
+			data:         `{"items": [{"maximum": 1, "exclusiveMaximum": true}], "anyOf": [{"id": "#a"}], "dependencies": {"a": ["b"], "c": {"id": "#c"}}}`,
+			defaultDraft: Draft4,
+			want:         `{"items":[{"exclusiveMaximum":1}],"anyOf":[{"$id":"#a"}],"dependencies":{"a":["b"],"c":{"$id":"#c"}}}`,
+		},
+		"draft-06 exclusive bounds unchanged": {
+			data:         `{"exclusiveMinimum": 1}`,
+			defaultDraft: Draft6,
+			want:         `{"exclusiveMinimum":1}`,
+		},
+		"draft-07 unchanged": {
+			data:         `{"id": "a", "exclusiveMinimum": true}`,
+			defaultDraft: Draft7,
+			want:         `{"id": "a", "exclusiveMinimum": true}`,
+		},
+		"custom $schema unchanged": {
+			data:         `{"$schema": "http://example.com/meta", "id": "a"}`,
+			defaultDraft: Draft4,
+			want:         `{"$schema":"http://example.com/meta","$id":"a"}`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Upgrade([]byte(test.data), test.defaultDraft)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("got  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSchema_UnmarshalJSON_upgrade(t *testing.T) {
+	var schema *Schema
+	data := `{"$schema": "http://json-schema.org/draft-04/schema#", "id": "http://example.com/a", "minimum": 0, "exclusiveMinimum": true}`
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if draft, _ := schema.Draft(); draft != Draft7 {
+		t.Errorf("got draft %v, want %v", draft, Draft7)
+	}
+	if schema.ID == nil || *schema.ID != "http://example.com/a" {
+		t.Errorf("got $id %v, want %q", schema.ID, "http://example.com/a")
+	}
+	if schema.Minimum != nil || schema.ExclusiveMinimum == nil || *schema.ExclusiveMinimum != 0 {
+		t.Errorf("got minimum %v and exclusiveMinimum %v, want only exclusiveMinimum 0", schema.Minimum, schema.ExclusiveMinimum)
+	}
+}
@@ -33,9 +33,9 @@ func (id ID) ResolveReference(ref []ReferenceToken) ID {
 
 // URI returns the URI for the ID, resolving the reference tokens relative to the base URI.
 //
-// If id.Base and id.ReferenceTokens are both nil, it returns nil.
+// If id.Base is nil and id.ReferenceTokens is empty, it returns nil.
 func (id ID) URI() *url.URL {
-	if id.ReferenceTokens == nil {
+	if len(id.ReferenceTokens) == 0 {
 		return id.Base // can be nil
 	}
 
@@ -60,25 +60,24 @@ func (id ID) String() string {
 // A ReferenceToken describes a one-level traversal in a JSON document. See [RFC
 // 6091](https://tools.ietf.org/html/rfc6901).
 type ReferenceToken struct {
-	Name    string // dereference object's named property
-	Keyword bool   // if Name != "", whether the Name is a JSON Schema keyword (e.g., "properties", "items", etc.)
+	Name    string // dereference object's named property (which may be empty)
+	Keyword bool   // if !IsIndex, whether the Name is a JSON Schema keyword (e.g., "properties", "items", etc.)
 	Index   int    // dereference array's index
+	IsIndex bool   // whether the token dereferences an array's index (Index) instead of a property (Name)
 }
 
-// EncodeReferenceTokens encodes the reference tokens to a string.
-//
-// TODO(sqs): Fully implement the encoding specified in
-// https://tools.ietf.org/html/rfc6901#section-6.
-func EncodeReferenceTokens(tokens []ReferenceToken) string {
-	parts := make([]string, len(tokens))
-	for i, token := range tokens {
-		var part string
-		if token.Name != "" {
-			part = token.Name
-		} else {
-			part = strconv.Itoa(token.Index)
-		}
-		parts[i] = part
+// String returns the string form of the reference token: the property name, or the decimal
+// representation of the array index.
+func (t ReferenceToken) String() string {
+	if t.IsIndex {
+		return strconv.Itoa(t.Index)
 	}
-	return strings.Join(parts, "/")
+	return t.Name
+}
+
+// EncodeReferenceTokens encodes the reference tokens to a string, escaping "~" and "/" in each
+// token as specified in [RFC 6901](https://tools.ietf.org/html/rfc6901#section-3). It is
+// equivalent to FormatJSONPointer without the leading "/".
+func EncodeReferenceTokens(tokens []ReferenceToken) string {
+	return strings.TrimPrefix(encodeJSONPointer(tokens), "/")
 }
@@ -0,0 +1,787 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Validate validates the JSON document instance against the schema. It returns nil if the instance
+// is valid.
+//
+// To validate multiple instances against the same schema, use NewValidator instead.
+func Validate(schema *Schema, instance []byte) error {
+	v, err := NewValidator(schema)
+	if err != nil {
+		return err
+	}
+	return v.Validate(instance)
+}
+
+// A Validator validates JSON documents against a JSON Schema (as specified in
+// [draft-handrews-json-schema-validation-01](https://tools.ietf.org/html/draft-handrews-json-schema-validation-01)
+// for draft-07, and in the corresponding 2019-09 and 2020-12 specifications). Each schema is
+// interpreted according to the draft selected by its "$schema" (see Schema.Draft).
+//
+// A Validator is safe for concurrent use by multiple goroutines.
+type Validator struct {
+	schema      *Schema
+	opts        ValidatorOptions
+	ids         map[*Schema]ID      // canonical ID of each (sub)schema, for error locations
+	dialects    map[*Schema]dialect // the dialect of each (sub)schema
+	byURI       map[string]*Schema  // all (sub)schemas, keyed by uriKey (for "$dynamicRef" anchors)
+	resources   map[*Schema]*Schema // (sub)schema -> the root of its nearest schema resource
+	refs        map[*Schema]*Schema // $ref-bearing schema -> referenced schema
+	dynamicRefs map[*Schema]*Schema // $dynamicRef- or $recursiveRef-bearing schema -> initially referenced schema
+	patterns    map[string]*regexp.Regexp
+}
+
+// ValidatorOptions configures a Validator.
+type ValidatorOptions struct {
+	// AssertFormat is whether the "format" keyword is asserted. If false, "format" is only an
+	// annotation and does not affect validation (which is the default behavior specified in
+	// draft-07). Unknown formats are never asserted.
+	AssertFormat bool
+
+	// Formats is the registry of format checkers used if AssertFormat is true. If nil,
+	// DefaultFormatRegistry is used.
+	Formats *FormatRegistry
+
+	// Loader loads the schema documents that "$ref"s refer to (other than the schema being
+	// validated against), as well as custom meta-schemas referred to by "$schema". If nil, such
+	// "$ref"s cause an error.
+	Loader Loader
+
+	// Draft is the draft of schemas that do not specify one with "$schema". If zero, DefaultDraft
+	// is used.
+	Draft Draft
+}
+
+// NewValidator returns a validator for the schema with the default options. It resolves all "$ref"
+// values in the schema and compiles all regular expressions, and it returns an error if any of
+// these operations fail.
+func NewValidator(schema *Schema) (*Validator, error) {
+	return NewValidatorWithOptions(schema, ValidatorOptions{})
+}
+
+// NewValidatorWithOptions is like NewValidator, but it uses the provided options.
+func NewValidatorWithOptions(schema *Schema, opts ValidatorOptions) (*Validator, error) {
+	if opts.Formats == nil {
+		opts.Formats = DefaultFormatRegistry
+	}
+
+	registry := NewRegistry(opts.Loader)
+	if opts.Draft != 0 {
+		registry.defaultDialect.draft = opts.Draft
+	}
+	if err := registry.add(schema, nil, nil, registry.defaultDialect); err != nil {
+		return nil, err
+	}
+
+	v := &Validator{
+		schema:      schema,
+		opts:        opts,
+		ids:         registry.ids,
+		dialects:    registry.dialects,
+		byURI:       registry.byURI,
+		resources:   map[*Schema]*Schema{},
+		refs:        map[*Schema]*Schema{},
+		dynamicRefs: map[*Schema]*Schema{},
+		patterns:    map[string]*regexp.Regexp{},
+	}
+	// Resolving a $ref can load additional schemas into the registry, which must be processed in turn.
+	for done := map[*Schema]bool{}; len(done) < len(registry.ids); {
+		var todo []*Schema
+		for s := range registry.ids {
+			if !done[s] {
+				todo = append(todo, s)
+				done[s] = true
+			}
+		}
+		for _, s := range todo {
+			if err := v.prepare(registry, s); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for s, id := range registry.ids {
+		v.resources[s] = registry.byURI[uriKey(id.Base)]
+	}
+	return v, nil
+}
+
+// prepare resolves the "$ref", "$dynamicRef" and "$recursiveRef" (if any), checks the value of
+// "multipleOf" and compiles the regular expressions of the schema.
+func (v *Validator) prepare(registry *Registry, s *Schema) error {
+	if s.Reference != nil {
+		target, err := registry.resolve(s)
+		if err != nil {
+			return err
+		}
+		v.refs[s] = target
+	}
+	d := registry.dialects[s]
+	for _, ref := range []struct {
+		keyword string
+		value   *string
+		enabled bool
+	}{
+		{keyword: "$recursiveRef", value: s.RecursiveRef, enabled: d.draft == Draft201909},
+		{keyword: "$dynamicRef", value: s.DynamicRef, enabled: d.draft >= Draft202012},
+	} {
+		if ref.value != nil && ref.enabled {
+			target, err := registry.resolveRef(registry.ids[s].Base, *ref.value)
+			if err != nil {
+				return ErrorAt(s.KeywordPos[ref.keyword], err)
+			}
+			v.dynamicRefs[s] = target
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf <= 0 {
+		return ErrorAt(s.KeywordPos["multipleOf"], fmt.Errorf("multipleOf must be greater than 0 (got %v)", *s.MultipleOf))
+	}
+	if s.Pattern != nil {
+		if err := v.compilePattern(*s.Pattern); err != nil {
+			return ErrorAt(s.KeywordPos["pattern"], err)
+		}
+	}
+	if s.PatternProperties != nil {
+		for pattern := range *s.PatternProperties {
+			if err := v.compilePattern(pattern); err != nil {
+				return ErrorAt(s.KeywordPos["patternProperties"], err)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Validator) compilePattern(pattern string) error {
+	if _, ok := v.patterns[pattern]; ok {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return errors.WithMessage(err, "invalid regular expression in schema")
+	}
+	v.patterns[pattern] = re
+	return nil
+}
+
+// Validate validates the JSON document instance, which must consist of a single JSON value
+// (optionally surrounded by whitespace). It returns nil if the instance is valid. If the instance
+// is invalid, the error is a *ValidationError whose causes describe each failed assertion.
+func (v *Validator) Validate(instance []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(instance))
+	dec.UseNumber() // preserve the precision of numbers
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return errors.WithMessage(err, "failed to decode JSON instance")
+	}
+	if err := dec.Decode(new(json.RawMessage)); err != io.EOF {
+		return errors.New("failed to decode JSON instance: invalid data after the top-level value")
+	}
+	return v.ValidateValue(value)
+}
+
+// ValidateValue validates an instance that has already been decoded from JSON (as with
+// json.Unmarshal into an interface{}). Numbers may be represented as float64 or json.Number values.
+// It returns nil if the instance is valid. If the instance is invalid, the error is a
+// *ValidationError whose causes describe each failed assertion.
+func (v *Validator) ValidateValue(instance interface{}) error {
+	if errs, _ := v.validate(v.schema, instance, []ReferenceToken{}, []ReferenceToken{}, nil); len(errs) > 0 {
+		return &ValidationError{
+			InstanceLocation:        []ReferenceToken{},
+			KeywordLocation:         []ReferenceToken{},
+			AbsoluteKeywordLocation: v.ids[v.schema],
+			Message:                 "instance is invalid",
+			Causes:                  errs,
+		}
+	}
+	return nil
+}
+
+// validate validates the instance against the schema. It returns an error for each failed
+// assertion, and the properties and items of the instance that the schema evaluated.
+//
+// The instance's location in the instance document is instLoc, and the schema's location relative
+// to the root schema (following "$ref"s) is schemaLoc. The dynamic scope (the schema resources
+// that were entered to reach the schema, outermost first) is scope.
+func (v *Validator) validate(schema *Schema, instance interface{}, instLoc, schemaLoc []ReferenceToken, scope []*Schema) (errs []*ValidationError, ev evaluated) {
+	newError := func(keyword string, causes []*ValidationError, format string, args ...interface{}) *ValidationError {
+		keywordToken := ReferenceToken{Name: keyword, Keyword: true}
+		return &ValidationError{
+			InstanceLocation:        instLoc,
+			KeywordLocation:         appendTokens(schemaLoc, keywordToken),
+			AbsoluteKeywordLocation: v.ids[schema].ResolveReference([]ReferenceToken{keywordToken}),
+			SchemaPosition:          schema.KeywordPos[keyword],
+			Keyword:                 keyword,
+			Message:                 fmt.Sprintf(format, args...),
+			Causes:                  causes,
+		}
+	}
+	fail := func(keyword string, format string, args ...interface{}) {
+		errs = append(errs, newError(keyword, nil, format, args...))
+	}
+	keywordLoc := func(tokens ...ReferenceToken) []ReferenceToken {
+		tokens[0].Keyword = true
+		return appendTokens(schemaLoc, tokens...)
+	}
+	// apply validates a value in the instance (such as a property or an item) against a subschema.
+	apply := func(s *Schema, value interface{}, valueLoc, loc []ReferenceToken) []*ValidationError {
+		sErrs, _ := v.validate(s, value, valueLoc, loc, scope)
+		return sErrs
+	}
+	// inPlace validates the instance against a subschema, keeping the subschema's annotations if
+	// it is valid.
+	inPlace := func(s *Schema, loc []ReferenceToken) []*ValidationError {
+		sErrs, sEv := v.validate(s, instance, instLoc, loc, scope)
+		if len(sErrs) == 0 {
+			ev.merge(sEv)
+		}
+		return sErrs
+	}
+
+	switch {
+	case schema.IsEmpty:
+		return nil, ev
+	case schema.IsNegated:
+		return []*ValidationError{{
+			InstanceLocation:        instLoc,
+			KeywordLocation:         schemaLoc,
+			AbsoluteKeywordLocation: v.ids[schema],
+			SchemaPosition:          schema.Pos,
+			Message:                 "no value is allowed by the false schema",
+		}}, ev
+	}
+
+	d, ok := v.dialects[schema]
+	if !ok {
+		d = defaultDialect
+	}
+	if resource := v.resources[schema]; resource != nil && (len(scope) == 0 || scope[len(scope)-1] != resource) {
+		scope = append(scope[:len(scope):len(scope)], resource)
+	}
+
+	if schema.Reference != nil {
+		// In draft-07, all other keywords are ignored when "$ref" is present
+		// (https://tools.ietf.org/html/draft-handrews-json-schema-01#section-8.3). In later drafts,
+		// "$ref" is an applicator like any other.
+		if d.draft < Draft201909 {
+			return v.validate(v.refs[schema], instance, instLoc, keywordLoc(ReferenceToken{Name: "$ref"}), scope)
+		}
+		errs = append(errs, inPlace(v.refs[schema], keywordLoc(ReferenceToken{Name: "$ref"}))...)
+	}
+	if target := v.dynamicRefs[schema]; target != nil {
+		keyword := "$dynamicRef"
+		if d.draft == Draft201909 {
+			keyword = "$recursiveRef"
+		}
+		errs = append(errs, inPlace(v.dynamicRefTarget(schema, target, scope), keywordLoc(ReferenceToken{Name: keyword}))...)
+	}
+
+	typ := instanceType(instance)
+
+	//
+	// Validation keywords for any instance type
+	//
+	if len(schema.Type) > 0 && d.validation {
+		var ok bool
+		for _, t := range schema.Type {
+			if t == typ || (t == IntegerType && typ == NumberType && isInteger(instance)) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			fail("type", "expected %s, but got %s", typeListString(schema.Type), typ)
+		}
+	}
+	if schema.Enum != nil && d.validation {
+		var ok bool
+		for _, e := range schema.Enum {
+			if equalJSON(instance, e) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			fail("enum", "value is not one of the allowed enum values")
+		}
+	}
+	if schema.Const != nil && d.validation && !equalJSON(instance, *schema.Const) {
+		fail("const", "value is not equal to the const value")
+	}
+
+	//
+	// Validation keywords for numeric instances
+	//
+	if n, ok := toNumber(instance); ok && d.validation {
+		if schema.MultipleOf != nil {
+			if m := floatRat(*schema.MultipleOf); m.Sign() > 0 && !n.isMultipleOf(m) {
+				fail("multipleOf", "%v is not a multiple of %v", instance, *schema.MultipleOf)
+			}
+		}
+		if schema.Maximum != nil && n.cmp(floatRat(*schema.Maximum)) > 0 {
+			fail("maximum", "%v is greater than the maximum %v", instance, *schema.Maximum)
+		}
+		if schema.ExclusiveMaximum != nil && n.cmp(floatRat(*schema.ExclusiveMaximum)) >= 0 {
+			fail("exclusiveMaximum", "%v is not less than the exclusive maximum %v", instance, *schema.ExclusiveMaximum)
+		}
+		if schema.Minimum != nil && n.cmp(floatRat(*schema.Minimum)) < 0 {
+			fail("minimum", "%v is less than the minimum %v", instance, *schema.Minimum)
+		}
+		if schema.ExclusiveMinimum != nil && n.cmp(floatRat(*schema.ExclusiveMinimum)) <= 0 {
+			fail("exclusiveMinimum", "%v is not greater than the exclusive minimum %v", instance, *schema.ExclusiveMinimum)
+		}
+	}
+
+	//
+	// Validation keywords for strings
+	//
+	if s, ok := instance.(string); ok {
+		length := int64(utf8.RuneCountInString(s))
+		if schema.MaxLength != nil && d.validation && length > *schema.MaxLength {
+			fail("maxLength", "string is longer than the maximum length %d", *schema.MaxLength)
+		}
+		if schema.MinLength != nil && d.validation && length < *schema.MinLength {
+			fail("minLength", "string is shorter than the minimum length %d", *schema.MinLength)
+		}
+		if schema.Pattern != nil && d.validation && !v.patterns[*schema.Pattern].MatchString(s) {
+			fail("pattern", "string does not match the pattern %q", *schema.Pattern)
+		}
+		if schema.Format != nil && v.opts.AssertFormat {
+			if checker := v.opts.Formats.Lookup(string(*schema.Format)); checker != nil {
+				if err := checker(s); err != nil {
+					fail("format", "string is not a valid %q: %s", *schema.Format, err)
+				}
+			}
+		}
+	}
+
+	//
+	// Validation keywords for arrays
+	//
+	if a, ok := instance.([]interface{}); ok {
+		// The schemas for items at specific positions ("items" as an array before 2020-12, and
+		// "prefixItems" in 2020-12), and the schema for all other items.
+		var (
+			prefix        []*Schema
+			prefixKeyword = "items"
+			rest          *Schema
+			restKeyword   = "items"
+		)
+		switch {
+		case d.draft >= Draft202012:
+			prefix, prefixKeyword = schema.PrefixItems, "prefixItems"
+			if schema.Items != nil {
+				rest = schema.Items.Schema
+			}
+		case schema.Items != nil && schema.Items.Schema != nil:
+			rest = schema.Items.Schema
+		case schema.Items != nil:
+			prefix = schema.Items.Schemas
+			rest, restKeyword = schema.AdditionalItems, "additionalItems"
+		}
+		for i, item := range a {
+			itemLoc := appendTokens(instLoc, ReferenceToken{Index: i, IsIndex: true})
+			switch {
+			case i < len(prefix):
+				errs = append(errs, apply(prefix[i], item, itemLoc, keywordLoc(ReferenceToken{Name: prefixKeyword}, ReferenceToken{Index: i, IsIndex: true}))...)
+			case rest != nil:
+				errs = append(errs, apply(rest, item, itemLoc, keywordLoc(ReferenceToken{Name: restKeyword}))...)
+			default:
+				continue
+			}
+			ev.addItem(i)
+		}
+		if schema.MaxItems != nil && d.validation && int64(len(a)) > *schema.MaxItems {
+			fail("maxItems", "array has more than the maximum %d items", *schema.MaxItems)
+		}
+		if schema.MinItems != nil && d.validation && int64(len(a)) < *schema.MinItems {
+			fail("minItems", "array has fewer than the minimum %d items", *schema.MinItems)
+		}
+		if schema.UniqueItems != nil && *schema.UniqueItems && d.validation {
+		unique:
+			for i := range a {
+				for j := i + 1; j < len(a); j++ {
+					if equalJSON(a[i], a[j]) {
+						fail("uniqueItems", "array items %d and %d are equal, but items must be unique", i, j)
+						break unique
+					}
+				}
+			}
+		}
+		if schema.Contains != nil {
+			var matches int64
+			for i, item := range a {
+				if len(apply(schema.Contains, item, appendTokens(instLoc, ReferenceToken{Index: i, IsIndex: true}), keywordLoc(ReferenceToken{Name: "contains"}))) == 0 {
+					matches++
+					// In 2020-12, the items that "contains" matches are evaluated.
+					if d.draft >= Draft202012 {
+						ev.addItem(i)
+					}
+				}
+			}
+			switch {
+			case d.draft >= Draft201909 && schema.MinContains != nil:
+				if matches < *schema.MinContains {
+					fail("minContains", "array contains %d items that are valid against the contains schema, but the minimum is %d", matches, *schema.MinContains)
+				}
+			case matches == 0:
+				fail("contains", "array does not contain an item that is valid against the contains schema")
+			}
+			if d.draft >= Draft201909 && schema.MaxContains != nil && matches > *schema.MaxContains {
+				fail("maxContains", "array contains %d items that are valid against the contains schema, but the maximum is %d", matches, *schema.MaxContains)
+			}
+		}
+	}
+
+	//
+	// Validation keywords for objects
+	//
+	if o, ok := instance.(map[string]interface{}); ok {
+		// Visit properties in a deterministic order.
+		names := make([]string, 0, len(o))
+		for name := range o {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if schema.MaxProperties != nil && d.validation && int64(len(o)) > *schema.MaxProperties {
+			fail("maxProperties", "object has more than the maximum %d properties", *schema.MaxProperties)
+		}
+		if schema.MinProperties != nil && d.validation && int64(len(o)) < *schema.MinProperties {
+			fail("minProperties", "object has fewer than the minimum %d properties", *schema.MinProperties)
+		}
+		if d.validation {
+			for _, name := range schema.Required {
+				if _, ok := o[name]; !ok {
+					fail("required", "required property %q is missing", name)
+				}
+			}
+		}
+		for _, name := range names {
+			propLoc := appendTokens(instLoc, ReferenceToken{Name: name})
+			var matched bool
+			if schema.Properties != nil {
+				if prop, ok := (*schema.Properties)[name]; ok {
+					matched = true
+					errs = append(errs, apply(prop, o[name], propLoc, keywordLoc(ReferenceToken{Name: "properties"}, ReferenceToken{Name: name}))...)
+				}
+			}
+			if schema.PatternProperties != nil {
+				for pattern, prop := range *schema.PatternProperties {
+					if v.patterns[pattern].MatchString(name) {
+						matched = true
+						errs = append(errs, apply(prop, o[name], propLoc, keywordLoc(ReferenceToken{Name: "patternProperties"}, ReferenceToken{Name: pattern}))...)
+					}
+				}
+			}
+			if !matched && schema.AdditionalProperties != nil {
+				matched = true
+				errs = append(errs, apply(schema.AdditionalProperties, o[name], propLoc, keywordLoc(ReferenceToken{Name: "additionalProperties"}))...)
+			}
+			if matched {
+				ev.addProp(name)
+			}
+		}
+		// In 2019-09, "dependencies" was split into "dependentRequired" and "dependentSchemas".
+		if schema.Dependencies != nil && d.draft < Draft201909 {
+			for _, name := range names {
+				dep := (*schema.Dependencies)[name]
+				if dep == nil {
+					continue
+				}
+				if dep.Schema != nil {
+					errs = append(errs, inPlace(dep.Schema, keywordLoc(ReferenceToken{Name: "dependencies"}, ReferenceToken{Name: name}))...)
+				}
+				for _, req := range dep.RequiredProperties {
+					if _, ok := o[req]; !ok {
+						fail("dependencies", "property %q is required by property %q, but it is missing", req, name)
+					}
+				}
+			}
+		}
+		if schema.DependentRequired != nil && d.draft >= Draft201909 && d.validation {
+			for _, name := range names {
+				for _, req := range (*schema.DependentRequired)[name] {
+					if _, ok := o[req]; !ok {
+						fail("dependentRequired", "property %q is required by property %q, but it is missing", req, name)
+					}
+				}
+			}
+		}
+		if schema.DependentSchemas != nil && d.draft >= Draft201909 {
+			for _, name := range names {
+				if dep := (*schema.DependentSchemas)[name]; dep != nil {
+					errs = append(errs, inPlace(dep, keywordLoc(ReferenceToken{Name: "dependentSchemas"}, ReferenceToken{Name: name}))...)
+				}
+			}
+		}
+		if schema.PropertyNames != nil {
+			for _, name := range names {
+				errs = append(errs, apply(schema.PropertyNames, name, appendTokens(instLoc, ReferenceToken{Name: name}), keywordLoc(ReferenceToken{Name: "propertyNames"}))...)
+			}
+		}
+	}
+
+	//
+	// Keywords for applying subschemas with boolean logic and conditionally
+	//
+	if len(schema.AllOf) > 0 {
+		var causes []*ValidationError
+		for i, s := range schema.AllOf {
+			causes = append(causes, inPlace(s, keywordLoc(ReferenceToken{Name: "allOf"}, ReferenceToken{Index: i, IsIndex: true}))...)
+		}
+		if len(causes) > 0 {
+			errs = append(errs, newError("allOf", causes, "value is not valid against all of the allOf schemas"))
+		}
+	}
+	if len(schema.AnyOf) > 0 {
+		// All of the schemas are evaluated (even after one is valid), because the annotations of
+		// each valid schema are needed for "unevaluatedProperties" and "unevaluatedItems".
+		var causes []*ValidationError
+		var ok bool
+		for i, s := range schema.AnyOf {
+			sErrs := inPlace(s, keywordLoc(ReferenceToken{Name: "anyOf"}, ReferenceToken{Index: i, IsIndex: true}))
+			if len(sErrs) == 0 {
+				ok = true
+			}
+			causes = append(causes, sErrs...)
+		}
+		if !ok {
+			errs = append(errs, newError("anyOf", causes, "value is not valid against any of the anyOf schemas"))
+		}
+	}
+	if len(schema.OneOf) > 0 {
+		var causes []*ValidationError
+		var valid []int
+		for i, s := range schema.OneOf {
+			sErrs := inPlace(s, keywordLoc(ReferenceToken{Name: "oneOf"}, ReferenceToken{Index: i, IsIndex: true}))
+			if len(sErrs) == 0 {
+				valid = append(valid, i)
+			}
+			causes = append(causes, sErrs...)
+		}
+		switch {
+		case len(valid) == 0:
+			errs = append(errs, newError("oneOf", causes, "value is not valid against any of the oneOf schemas"))
+		case len(valid) > 1:
+			fail("oneOf", "value must be valid against exactly 1 of the oneOf schemas, but it is valid against the schemas at indexes %v", valid)
+		}
+	}
+	if schema.Not != nil && len(apply(schema.Not, instance, instLoc, keywordLoc(ReferenceToken{Name: "not"}))) == 0 {
+		fail("not", "value must not be valid against the not schema")
+	}
+	if schema.If != nil {
+		if len(inPlace(schema.If, keywordLoc(ReferenceToken{Name: "if"}))) == 0 {
+			if schema.Then != nil {
+				errs = append(errs, inPlace(schema.Then, keywordLoc(ReferenceToken{Name: "then"}))...)
+			}
+		} else if schema.Else != nil {
+			errs = append(errs, inPlace(schema.Else, keywordLoc(ReferenceToken{Name: "else"}))...)
+		}
+	}
+
+	//
+	// Custom keywords (see RegisterKeyword)
+	//
+	for _, name := range schema.extensionNames() {
+		keyword := LookupKeyword(name)
+		if keyword == nil || keyword.Validate == nil {
+			continue
+		}
+		loc := keywordLoc(ReferenceToken{Name: name})
+		isValid := func(s *Schema, value interface{}) bool {
+			sErrs, _ := v.validate(s, value, instLoc, loc, scope)
+			return len(sErrs) == 0
+		}
+		if err := keyword.Validate(schema.Extensions[name], instance, isValid); err != nil {
+			fail(name, "%s", err)
+		}
+	}
+
+	//
+	// Keywords for unevaluated locations (2019-09 and later), which depend on the annotations of
+	// all of the other keywords
+	//
+	if schema.UnevaluatedItems != nil && d.draft >= Draft201909 {
+		if a, ok := instance.([]interface{}); ok {
+			for i, item := range a {
+				if !ev.items[i] {
+					errs = append(errs, apply(schema.UnevaluatedItems, item, appendTokens(instLoc, ReferenceToken{Index: i, IsIndex: true}), keywordLoc(ReferenceToken{Name: "unevaluatedItems"}))...)
+					ev.addItem(i)
+				}
+			}
+		}
+	}
+	if schema.UnevaluatedProperties != nil && d.draft >= Draft201909 {
+		if o, ok := instance.(map[string]interface{}); ok {
+			names := make([]string, 0, len(o))
+			for name := range o {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if !ev.props[name] {
+					errs = append(errs, apply(schema.UnevaluatedProperties, o[name], appendTokens(instLoc, ReferenceToken{Name: name}), keywordLoc(ReferenceToken{Name: "unevaluatedProperties"}))...)
+					ev.addProp(name)
+				}
+			}
+		}
+	}
+
+	return errs, ev
+}
+
+// dynamicRefTarget returns the schema that the "$dynamicRef" (or, in 2019-09, "$recursiveRef") of
+// schema refers to in the dynamic scope, given the schema that it initially resolved to.
+func (v *Validator) dynamicRefTarget(schema, target *Schema, scope []*Schema) *Schema {
+	if schema.RecursiveRef != nil && v.dialects[schema].draft == Draft201909 {
+		// If the initial target has "$recursiveAnchor": true, use the outermost schema resource in
+		// the dynamic scope that also does.
+		if target.RecursiveAnchor == nil || !*target.RecursiveAnchor {
+			return target
+		}
+		for _, s := range scope {
+			if s.RecursiveAnchor != nil && *s.RecursiveAnchor {
+				return s
+			}
+		}
+		return target
+	}
+
+	// If the initial target has a "$dynamicAnchor" that matches the fragment, use the outermost
+	// schema resource in the dynamic scope that has the same "$dynamicAnchor".
+	ref, err := url.Parse(*schema.DynamicRef)
+	if err != nil || target.DynamicAnchor == nil || *target.DynamicAnchor != ref.Fragment {
+		return target
+	}
+	for _, s := range scope {
+		u := *v.ids[s].Base
+		u.Fragment = ref.Fragment
+		if t := v.byURI[uriKey(&u)]; t != nil && t.DynamicAnchor != nil && *t.DynamicAnchor == ref.Fragment {
+			return t
+		}
+	}
+	return target
+}
+
+// evaluated records the properties and items of an instance that were evaluated by a schema (and
+// its valid subschemas). These are the annotations that "unevaluatedProperties" and
+// "unevaluatedItems" depend on.
+type evaluated struct {
+	props map[string]bool
+	items map[int]bool
+}
+
+func (e *evaluated) addProp(name string) {
+	if e.props == nil {
+		e.props = map[string]bool{}
+	}
+	e.props[name] = true
+}
+
+func (e *evaluated) addItem(index int) {
+	if e.items == nil {
+		e.items = map[int]bool{}
+	}
+	e.items[index] = true
+}
+
+func (e *evaluated) merge(other evaluated) {
+	for name := range other.props {
+		e.addProp(name)
+	}
+	for index := range other.items {
+		e.addItem(index)
+	}
+}
+
+// instanceType returns the JSON Schema primitive type of a decoded JSON value. Integers are
+// reported as NumberType (use isInteger to distinguish them).
+func instanceType(instance interface{}) PrimitiveType {
+	switch instance.(type) {
+	case nil:
+		return NullType
+	case bool:
+		return BooleanType
+	case string:
+		return StringType
+	case []interface{}:
+		return ArrayType
+	case map[string]interface{}:
+		return ObjectType
+	}
+	if _, ok := toNumber(instance); ok {
+		return NumberType
+	}
+	return UnspecifiedType
+}
+
+func isInteger(instance interface{}) bool {
+	n, ok := toNumber(instance)
+	return ok && n.isInt()
+}
+
+func typeListString(l PrimitiveTypeList) string {
+	if len(l) == 1 {
+		return l[0].String()
+	}
+	s := make([]string, len(l))
+	for i, t := range l {
+		s[i] = t.String()
+	}
+	return "one of " + strings.Join(s, ", ")
+}
+
+// equalJSON reports whether the decoded JSON values are equal. Numbers are equal if they have the
+// same mathematical value (e.g., 1 and 1.0 are equal).
+func equalJSON(a, b interface{}) bool {
+	if na, ok := toNumber(a); ok {
+		nb, ok := toNumber(b)
+		return ok && na.equal(nb)
+	}
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSON(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, va := range a {
+			vb, ok := b[k]
+			if !ok || !equalJSON(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
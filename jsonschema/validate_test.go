@@ -0,0 +1,217 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		schema   string
+		instance string
+		valid    bool
+	}{
+		"true schema":                {schema: `true`, instance: `1`, valid: true},
+		"false schema":               {schema: `false`, instance: `1`, valid: false},
+		"trailing whitespace":        {schema: `true`, instance: "1 \n", valid: true},
+		"trailing data":              {schema: `true`, instance: `{} trailing`, valid: false},
+		"multiple values":            {schema: `true`, instance: `1 2`, valid: false},
+		"type match":                 {schema: `{"type":"string"}`, instance: `"a"`, valid: true},
+		"type mismatch":              {schema: `{"type":"string"}`, instance: `1`, valid: false},
+		"integer with zero fraction": {schema: `{"type":"integer"}`, instance: `1.0`, valid: true},
+		"big integer":                {schema: `{"type":"integer"}`, instance: `12345678910111213141516171819202122232425262728293031`, valid: true},
+		"multipleOf decimal":         {schema: `{"multipleOf":0.0001}`, instance: `0.0075`, valid: true},
+		"huge number type":           {schema: `{"type":"number"}`, instance: `1e100000000`, valid: true},
+		"huge number integer":        {schema: `{"type":"integer"}`, instance: `1.5e100000000`, valid: true},
+		"huge number maximum":        {schema: `{"maximum":3}`, instance: `1e100000000`, valid: false},
+		"huge negative number":       {schema: `{"minimum":-3}`, instance: `-1e100000000`, valid: false},
+		"huge number multipleOf":     {schema: `{"multipleOf":0.75}`, instance: `3e100000000`, valid: true},
+		"huge number not multipleOf": {schema: `{"multipleOf":7}`, instance: `1e100000000`, valid: false},
+		"tiny number minimum":        {schema: `{"exclusiveMinimum":0}`, instance: `1e-100000000`, valid: true},
+		"tiny number maximum":        {schema: `{"maximum":0}`, instance: `1e-100000000`, valid: false},
+		"tiny number multipleOf":     {schema: `{"multipleOf":1e-300}`, instance: `1e-100000000`, valid: false},
+		"huge numbers uniqueItems":   {schema: `{"uniqueItems":true}`, instance: `[1e100000000,10.0e99999999]`, valid: false},
+		"enum numeric equality":      {schema: `{"enum":[1,"a"]}`, instance: `1.0`, valid: true},
+		"const object":               {schema: `{"const":{"a":[1]}}`, instance: `{"a":[1]}`, valid: true},
+		"uniqueItems":                {schema: `{"uniqueItems":true}`, instance: `[{"a":1},{"a":1.0}]`, valid: false},
+		"items list with additionalItems": {
+			schema:   `{"items":[{"type":"string"}],"additionalItems":false}`,
+			instance: `["a",1]`,
+			valid:    false,
+		},
+		"additionalProperties": {
+			schema:   `{"properties":{"a":{}},"patternProperties":{"^b":{}},"additionalProperties":false}`,
+			instance: `{"a":1,"bc":2}`,
+			valid:    true,
+		},
+		"dependencies": {
+			schema:   `{"dependencies":{"a":["b"]}}`,
+			instance: `{"a":1}`,
+			valid:    false,
+		},
+		"oneOf matches both": {
+			schema:   `{"oneOf":[{"type":"integer"},{"minimum":2}]}`,
+			instance: `3`,
+			valid:    false,
+		},
+		"if then": {
+			schema:   `{"if":{"type":"string"},"then":{"minLength":2},"else":{"type":"number"}}`,
+			instance: `"a"`,
+			valid:    false,
+		},
+		"ref to definition": {
+			schema:   `{"definitions":{"a":{"type":"string"}},"properties":{"b":{"$ref":"#/definitions/a"}}}`,
+			instance: `{"b":1}`,
+			valid:    false,
+		},
+		"ref to $id": {
+			schema:   `{"$id":"http://example.com/root.json","definitions":{"a":{"$id":"#foo","type":"string"}},"items":{"$ref":"#foo"}}`,
+			instance: `["a"]`,
+			valid:    true,
+		},
+		"ref to escaped pointer": {
+			schema:   `{"definitions":{"a/b":{"type":"string"},"c~d":{"type":"integer"},"e f":{"type":"boolean"}},"items":[{"$ref":"#/definitions/a~1b"},{"$ref":"#/definitions/c~0d"},{"$ref":"#/definitions/e%20f"}]}`,
+			instance: `["a",1,true]`,
+			valid:    true,
+		},
+		"minContains": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","contains":{"type":"string"},"minContains":2}`,
+			instance: `["a",1]`,
+			valid:    false,
+		},
+		"minContains zero": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","contains":{"type":"string"},"minContains":0}`,
+			instance: `[1,2]`,
+			valid:    true,
+		},
+		"maxContains": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","contains":{"type":"string"},"maxContains":1}`,
+			instance: `["a","b",1]`,
+			valid:    false,
+		},
+		"maxContains with zero fraction": {
+			schema:   `{"$schema":"https://json-schema.org/draft/2020-12/schema","contains":{"type":"string"},"maxContains":2.0}`,
+			instance: `["a","b",1]`,
+			valid:    true,
+		},
+		"minLength with zero fraction": {
+			schema:   `{"minLength":2.0}`,
+			instance: `"a"`,
+			valid:    false,
+		},
+		"ref relative to nested $id": {
+			schema:   `{"$id":"http://example.com/root.json","items":{"$id":"item.json","definitions":{"a":{"type":"string"}},"items":{"$ref":"#/definitions/a"}}}`,
+			instance: `[["a",1]]`,
+			valid:    false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			err := Validate(&schema, []byte(test.instance))
+			if valid := err == nil; valid != test.valid {
+				t.Errorf("got valid %v (error: %v), want %v", valid, err, test.valid)
+			}
+		})
+	}
+}
+
+func TestNewValidator(t *testing.T) {
+	tests := map[string]string{
+		"unresolvable $ref": `{"$ref":"#/definitions/a"}`,
+		"invalid pattern":   `{"pattern":"("}`,
+		"zero multipleOf":   `{"multipleOf":0}`,
+	}
+	for name, schemaJSON := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewValidator(&schema); err == nil {
+				t.Error("got err == nil, want error")
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{
+  "$id": "http://example.com/s.json",
+  "properties": {
+    "a": {"$ref": "#/definitions/b"},
+    "c": {"anyOf": [{"type": "string"}, {"type": "boolean"}]}
+  },
+  "definitions": {"b": {"minLength": 2}}
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Validate(&schema, []byte(`{"a":"x","c":1}`))
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("got error %v (%T), want *ValidationError", err, err)
+	}
+	if len(verr.Causes) != 2 {
+		t.Fatalf("got %d causes, want 2", len(verr.Causes))
+	}
+
+	type errorInfo struct {
+		instanceLocation, keywordLocation, absoluteKeywordLocation, keyword string
+		causes                                                              int
+	}
+	info := func(e *ValidationError) errorInfo {
+		return errorInfo{
+			instanceLocation:        encodeJSONPointer(e.InstanceLocation),
+			keywordLocation:         encodeJSONPointer(e.KeywordLocation),
+			absoluteKeywordLocation: e.AbsoluteKeywordLocation.String(),
+			keyword:                 e.Keyword,
+			causes:                  len(e.Causes),
+		}
+	}
+	want := []errorInfo{
+		{
+			instanceLocation:        "/a",
+			keywordLocation:         "/properties/a/$ref/minLength",
+			absoluteKeywordLocation: "http://example.com/s.json#/definitions/b/minLength",
+			keyword:                 "minLength",
+		},
+		{
+			instanceLocation:        "/c",
+			keywordLocation:         "/properties/c/anyOf",
+			absoluteKeywordLocation: "http://example.com/s.json#/properties/c/anyOf",
+			keyword:                 "anyOf",
+			causes:                  2,
+		},
+	}
+	for i, cause := range verr.Causes {
+		if got := info(cause); got != want[i] {
+			t.Errorf("cause %d: got %+v, want %+v", i, got, want[i])
+		}
+	}
+	if got, want := encodeJSONPointer(verr.Causes[1].Causes[1].KeywordLocation), "/properties/c/anyOf/1/type"; got != want {
+		t.Errorf("got nested cause keyword location %q, want %q", got, want)
+	}
+}
+
+func TestValidationError_emptyPropertyName(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{"properties": {"": {"type": "string"}}}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	err := Validate(&schema, []byte(`{"":1}`))
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Causes) != 1 {
+		t.Fatalf("got error %v, want *ValidationError with 1 cause", err)
+	}
+	if got, want := encodeJSONPointer(verr.Causes[0].InstanceLocation), "/"; got != want {
+		t.Errorf("got instance location %q, want %q", got, want)
+	}
+	if got, want := encodeJSONPointer(verr.Causes[0].KeywordLocation), "/properties//type"; got != want {
+		t.Errorf("got keyword location %q, want %q", got, want)
+	}
+}
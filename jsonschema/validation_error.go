@@ -0,0 +1,63 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A ValidationError describes why an instance is invalid against a schema.
+//
+// The error returned by (*Validator).Validate describes the instance as a whole (and has an empty
+// Keyword). Its Causes describe each failed assertion. The "allOf", "anyOf" and "oneOf" keywords
+// produce errors whose Causes describe the failed assertions of their subschemas.
+type ValidationError struct {
+	// InstanceLocation is the location of the invalid value in the instance document.
+	InstanceLocation []ReferenceToken
+
+	// KeywordLocation is the location of the failed keyword relative to the root schema. It
+	// includes each "$ref" that was followed to reach the keyword.
+	KeywordLocation []ReferenceToken
+
+	// AbsoluteKeywordLocation is the absolute location of the failed keyword, relative to the
+	// nearest enclosing schema with an "$id" (or the root schema). It does not include any "$ref"
+	// traversals.
+	AbsoluteKeywordLocation ID
+
+	// InstancePosition is the position of the invalid value in the source of the instance
+	// document, if known (it is only set by CheckSchema).
+	InstancePosition Position
+
+	// SchemaPosition is the position of the failed keyword's value in the source of the schema, if
+	// known (see Schema.KeywordPos).
+	SchemaPosition Position
+
+	Keyword string // the failed keyword (e.g., "minLength")
+	Message string // a human-readable description of the failure
+
+	// Causes are the errors of the subschemas that caused this error (for errors whose keyword
+	// applies subschemas, such as "anyOf").
+	Causes []*ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	if e.Keyword == "" && len(e.Causes) > 0 {
+		if len(e.Causes) == 1 {
+			return e.Causes[0].Error()
+		}
+		return fmt.Sprintf("%d validation errors: %s", len(e.Causes), joinValidationErrors(e.Causes))
+	}
+
+	msg := fmt.Sprintf("#%s: %s", encodeJSONPointer(e.InstanceLocation), e.Message)
+	if len(e.Causes) > 0 {
+		msg += " (" + joinValidationErrors(e.Causes) + ")"
+	}
+	return msg
+}
+
+func joinValidationErrors(errs []*ValidationError) string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
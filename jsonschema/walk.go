@@ -1,5 +1,7 @@
 package jsonschema
 
+import "github.com/pkg/errors"
+
 // A Visitor's Visit method is invoked for each schema (with the relative reference tokens
 // identifying it) to encountered by Walk.  If the result visitor w is not nil, Walk visits each of
 // the subschemas of schema with the visitor w, followed by a call of w.Visit(nil).
@@ -11,20 +13,25 @@ type Visitor interface {
 // must not be nil. If the visitor w returned by v.Visit(schema) is not nil, Walk is invoked
 // recursively with visitor w for each of the non-nil children of schema, followed by a call of
 // w.Visit(nil).
+//
+// The traversal order is deterministic. The children are visited in the order of the keywords'
+// fields in the Schema struct type definition. The schemas in an object of schemas (such as
+// "properties") are visited in the order returned by Schema.OrderedKeys (document order, then
+// sorted by name), and the schemas in an array of schemas are visited in array order. The
+// subschemas of custom keywords (see Keyword.Subschemas) are visited last, sorted by keyword.
 func Walk(v Visitor, schema *Schema) {
 	walk(v, schema, nil)
 }
 
 func walk(v Visitor, schema *Schema, rel []ReferenceToken) {
+	if schema == nil {
+		return // a null value in an object or array of schemas
+	}
 	if v = v.Visit(schema, rel); v == nil {
 		return
 	}
 
-	// TODO(sqs): When unmarshaling fields that can be singular or an array in Go, we don't record
-	// the input type (singular or array). This means that the index ReferenceTokens we use below
-	// might be incorrect when i==0 and the list only has a single item.
-
-	// Walk children. The order of the fields matches the their order in the Schema struct type
+	// Walk children. The order of the fields matches their order in the Schema struct type
 	// definition.
 	if schema.AdditionalItems != nil {
 		walk(v, schema.AdditionalItems, []ReferenceToken{{Name: "additionalItems"}})
@@ -33,26 +40,28 @@ func walk(v Visitor, schema *Schema, rel []ReferenceToken) {
 		walk(v, schema.AdditionalProperties, []ReferenceToken{{Name: "additionalProperties"}})
 	}
 	for i, s := range schema.AllOf {
-		walk(v, s, []ReferenceToken{{Name: "allOf", Keyword: true}, {Index: i}})
+		walk(v, s, []ReferenceToken{{Name: "allOf", Keyword: true}, {Index: i, IsIndex: true}})
 	}
 	for i, s := range schema.AnyOf {
-		walk(v, s, []ReferenceToken{{Name: "anyOf", Keyword: true}, {Index: i}})
+		walk(v, s, []ReferenceToken{{Name: "anyOf", Keyword: true}, {Index: i, IsIndex: true}})
 	}
 	if schema.Contains != nil {
 		walk(v, schema.Contains, []ReferenceToken{{Name: "contains", Keyword: true}})
 	}
-	if schema.Definitions != nil {
-		for name, s := range *schema.Definitions {
-			walk(v, s, []ReferenceToken{{Name: "definitions", Keyword: true}, {Name: name}})
-		}
+	for _, name := range schema.OrderedKeys("definitions") {
+		walk(v, (*schema.Definitions)[name], []ReferenceToken{{Name: "definitions", Keyword: true}, {Name: name}})
 	}
-	if schema.Dependencies != nil {
-		for name, s := range *schema.Dependencies {
-			if s != nil && s.Schema != nil {
-				walk(v, s.Schema, []ReferenceToken{{Name: "dependencies", Keyword: true}, {Name: name}})
-			}
+	for _, name := range schema.OrderedKeys("$defs") {
+		walk(v, (*schema.Defs)[name], []ReferenceToken{{Name: "$defs", Keyword: true}, {Name: name}})
+	}
+	for _, name := range schema.OrderedKeys("dependencies") {
+		if s := (*schema.Dependencies)[name]; s != nil && s.Schema != nil {
+			walk(v, s.Schema, []ReferenceToken{{Name: "dependencies", Keyword: true}, {Name: name}})
 		}
 	}
+	for _, name := range schema.OrderedKeys("dependentSchemas") {
+		walk(v, (*schema.DependentSchemas)[name], []ReferenceToken{{Name: "dependentSchemas", Keyword: true}, {Name: name}})
+	}
 	if schema.Else != nil {
 		walk(v, schema.Else, []ReferenceToken{{Name: "else", Keyword: true}})
 	}
@@ -64,21 +73,23 @@ func walk(v Visitor, schema *Schema, rel []ReferenceToken) {
 			walk(v, schema.Items.Schema, []ReferenceToken{{Name: "items", Keyword: true}})
 		}
 		for i, s := range schema.Items.Schemas {
-			walk(v, s, []ReferenceToken{{Name: "items", Keyword: true}, {Index: i}})
+			walk(v, s, []ReferenceToken{{Name: "items", Keyword: true}, {Index: i, IsIndex: true}})
 		}
 	}
+	if schema.Not != nil {
+		walk(v, schema.Not, []ReferenceToken{{Name: "not", Keyword: true}})
+	}
 	for i, s := range schema.OneOf {
-		walk(v, s, []ReferenceToken{{Name: "oneOf", Keyword: true}, {Index: i}})
+		walk(v, s, []ReferenceToken{{Name: "oneOf", Keyword: true}, {Index: i, IsIndex: true}})
 	}
-	if schema.PatternProperties != nil {
-		for name, s := range *schema.PatternProperties {
-			walk(v, s, []ReferenceToken{{Name: "patternProperties", Keyword: true}, {Name: name}})
-		}
+	for _, name := range schema.OrderedKeys("patternProperties") {
+		walk(v, (*schema.PatternProperties)[name], []ReferenceToken{{Name: "patternProperties", Keyword: true}, {Name: name}})
 	}
-	if schema.Properties != nil {
-		for name, s := range *schema.Properties {
-			walk(v, s, []ReferenceToken{{Name: "properties", Keyword: true}, {Name: name}})
-		}
+	for i, s := range schema.PrefixItems {
+		walk(v, s, []ReferenceToken{{Name: "prefixItems", Keyword: true}, {Index: i, IsIndex: true}})
+	}
+	for _, name := range schema.OrderedKeys("properties") {
+		walk(v, (*schema.Properties)[name], []ReferenceToken{{Name: "properties", Keyword: true}, {Name: name}})
 	}
 	if schema.PropertyNames != nil {
 		walk(v, schema.PropertyNames, []ReferenceToken{{Name: "propertyNames", Keyword: true}})
@@ -86,6 +97,81 @@ func walk(v Visitor, schema *Schema, rel []ReferenceToken) {
 	if schema.Then != nil {
 		walk(v, schema.Then, []ReferenceToken{{Name: "then", Keyword: true}})
 	}
+	if schema.UnevaluatedItems != nil {
+		walk(v, schema.UnevaluatedItems, []ReferenceToken{{Name: "unevaluatedItems", Keyword: true}})
+	}
+	if schema.UnevaluatedProperties != nil {
+		walk(v, schema.UnevaluatedProperties, []ReferenceToken{{Name: "unevaluatedProperties", Keyword: true}})
+	}
+
+	for _, name := range schema.extensionNames() {
+		keyword := LookupKeyword(name)
+		if keyword == nil || keyword.Subschemas == nil {
+			continue
+		}
+		for _, sub := range keyword.Subschemas(schema.Extensions[name]) {
+			walk(v, sub.Schema, appendTokens([]ReferenceToken{{Name: name, Keyword: true}}, sub.Rel...))
+		}
+	}
 
 	v.Visit(nil, rel)
 }
+
+// SkipSubschemas is used as a return value from a pre WalkFunc to indicate that the subschemas of
+// the schema are to be skipped. It is not returned as an error by WalkSchema.
+var SkipSubschemas = errors.New("skip subschemas")
+
+// A WalkFunc is called by WalkSchema for each schema, with the reference tokens of the schema's
+// location relative to the root schema.
+//
+// If a pre WalkFunc returns SkipSubschemas, the subschemas of the schema (and the post WalkFunc for
+// the schema) are skipped. If a WalkFunc returns any other non-nil error, WalkSchema stops and
+// returns the error.
+type WalkFunc func(schema *Schema, path []ReferenceToken) error
+
+// WalkSchema traverses a JSON Schema in depth-first order (in the same order as Walk). For each
+// schema, it calls pre (if non-nil) before visiting the subschemas and post (if non-nil) after
+// visiting them. The path slice for each schema is newly allocated and is never modified by
+// WalkSchema, so the WalkFuncs may retain it. They must not modify it, because the same slice is
+// passed to pre and post.
+func WalkSchema(root *Schema, pre, post WalkFunc) error {
+	var err error
+	walk(&funcVisitor{pre: pre, post: post, err: &err}, root, nil)
+	return err
+}
+
+// funcVisitor implements Visitor by calling WalkFuncs.
+type funcVisitor struct {
+	pre, post WalkFunc
+	err       *error
+
+	schema *Schema // the schema whose subschemas are being visited (nil for the root visitor)
+	path   []ReferenceToken
+}
+
+// Visit implements Visitor.
+func (v *funcVisitor) Visit(schema *Schema, rel []ReferenceToken) Visitor {
+	if *v.err != nil {
+		return nil
+	}
+	if schema == nil {
+		// All of the subschemas of v.schema have been visited.
+		if v.post != nil && v.schema != nil {
+			*v.err = v.post(v.schema, v.path)
+		}
+		return nil
+	}
+
+	w := *v // copy
+	w.schema = schema
+	w.path = appendTokens(v.path, rel...)
+	if v.pre != nil {
+		if err := v.pre(schema, w.path); err == SkipSubschemas {
+			return nil
+		} else if err != nil {
+			*v.err = err
+			return nil
+		}
+	}
+	return &w
+}
@@ -0,0 +1,137 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+const walkTestSchema = `{
+  "properties": {
+    "z": {"type": "string"},
+    "a": {"items": [true, {"type": "integer"}]},
+    "m": {"not": {"type": "null"}}
+  },
+  "definitions": {"y": {}, "b": {}},
+  "dependencies": {"q": ["z"], "p": {"required": ["a"]}}
+}`
+
+func TestWalk_order(t *testing.T) {
+	var schema *Schema
+	if err := json.Unmarshal([]byte(walkTestSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	// Add a property that is not in the document, which is visited after the others.
+	(*schema.Properties)["c"] = &Schema{}
+
+	want := []string{
+		"",
+		"/definitions/y",
+		"/definitions/b",
+		"/dependencies/p",
+		"/properties/z",
+		"/properties/a",
+		"/properties/a/items/0",
+		"/properties/a/items/1",
+		"/properties/m",
+		"/properties/m/not",
+		"/properties/c",
+	}
+	for i := 0; i < 10; i++ {
+		var got []string
+		WalkSchema(schema, func(schema *Schema, path []ReferenceToken) error {
+			got = append(got, encodeJSONPointer(path))
+			return nil
+		}, nil)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestWalkSchema(t *testing.T) {
+	var schema *Schema
+	if err := json.Unmarshal([]byte(walkTestSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []string
+	record := func(event string) WalkFunc {
+		return func(schema *Schema, path []ReferenceToken) error {
+			events = append(events, event+" "+encodeJSONPointer(path))
+			return nil
+		}
+	}
+
+	t.Run("pre and post", func(t *testing.T) {
+		events = nil
+		if err := WalkSchema((*schema.Properties)["a"], record("pre"), record("post")); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"pre ", "pre /items/0", "post /items/0", "pre /items/1", "post /items/1", "post "}
+		if !reflect.DeepEqual(events, want) {
+			t.Errorf("got %q, want %q", events, want)
+		}
+	})
+
+	t.Run("retained paths", func(t *testing.T) {
+		var paths [][]ReferenceToken
+		var pointers []string
+		if err := WalkSchema(schema, func(schema *Schema, path []ReferenceToken) error {
+			paths = append(paths, path)
+			pointers = append(pointers, encodeJSONPointer(path))
+			return nil
+		}, nil); err != nil {
+			t.Fatal(err)
+		}
+		for i, path := range paths {
+			if got := encodeJSONPointer(path); got != pointers[i] {
+				t.Errorf("retained path changed from %q to %q", pointers[i], got)
+			}
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		events = nil
+		pre := func(schema *Schema, path []ReferenceToken) error {
+			record("pre")(schema, path)
+			if len(path) > 0 && path[0].Name == "properties" {
+				return SkipSubschemas
+			}
+			return nil
+		}
+		if err := WalkSchema(schema, pre, record("post")); err != nil {
+			t.Fatal(err)
+		}
+		for _, event := range events {
+			if strings.HasPrefix(event, "post /properties") || strings.Count(event, "/") > 2 {
+				t.Errorf("unexpected event %q", event)
+			}
+		}
+		if want := "pre /properties/m"; events[len(events)-2] != want {
+			t.Errorf("got events %q, want %q before the root's post", events, want)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		events = nil
+		wantErr := errors.New("x")
+		pre := func(schema *Schema, path []ReferenceToken) error {
+			record("pre")(schema, path)
+			if len(path) > 0 && path[0].Name == "dependencies" {
+				return wantErr
+			}
+			return nil
+		}
+		if err := WalkSchema(schema, pre, record("post")); err != wantErr {
+			t.Fatalf("got error %v, want %v", err, wantErr)
+		}
+		want := []string{"pre ", "pre /definitions/y", "post /definitions/y", "pre /definitions/b", "post /definitions/b", "pre /dependencies/p"}
+		if !reflect.DeepEqual(events, want) {
+			t.Errorf("got %q, want %q", events, want)
+		}
+	})
+}
@@ -0,0 +1,193 @@
+// Package lint checks JSON Schema documents for common mistakes that are valid JSON Schema but
+// are probably not what the author intended (such as a "required" property that is not defined in
+// "properties").
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// A Problem is a mistake found in a schema by a rule.
+type Problem struct {
+	Rule     string              // the name of the rule that found the problem
+	Location string              // the JSON Pointer of the schema in its document
+	Pos      jsonschema.Position // the position of the problem in the source, if known
+	Message  string              // a human-readable description of the problem
+}
+
+// String returns the problem in the form "position: message (rule)".
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s (%s)", p.Pos, p.Message, p.Rule)
+}
+
+// A Rule checks each schema in a document for a kind of mistake.
+type Rule struct {
+	Name string // the name of the rule (such as "unused-definition")
+	Doc  string // a description of the mistakes that the rule finds
+
+	check func(l *linter, s schema)
+}
+
+// Options configures Lint.
+type Options struct {
+	// Disabled is the set of names of the rules that are not run. All other rules in Rules are
+	// run.
+	Disabled map[string]bool
+
+	// URI is the URI that the document was retrieved from (used to resolve relative "$ref"
+	// values), or nil if unknown.
+	URI *url.URL
+
+	// Loader loads the documents that "$ref" values refer to (other than the document being
+	// linted). If nil, such "$ref"s are reported as unresolvable.
+	Loader jsonschema.Loader
+}
+
+// Lint checks the root schema document and all of its subschemas with each rule that is not
+// disabled, and it returns the problems that they find, sorted by position (problems whose
+// positions are unknown come last, in the order in which the schemas are walked). It returns an
+// error if opts.Disabled contains an unknown rule name.
+func Lint(root *jsonschema.Schema, opts Options) ([]Problem, error) {
+	for name := range opts.Disabled {
+		if LookupRule(name) == nil {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+	}
+
+	l := &linter{registry: jsonschema.NewRegistry(opts.Loader), pointers: map[*jsonschema.Schema]string{}}
+	jsonschema.WalkSchema(root, func(s *jsonschema.Schema, path []jsonschema.ReferenceToken) error {
+		l.schemas = append(l.schemas, schema{Schema: s, path: path})
+		l.pointers[s] = pointer(path)
+		return nil
+	}, nil)
+	if err := l.registry.Add(root, opts.URI); err != nil {
+		return nil, err
+	}
+	l.resolveRefs()
+
+	for _, s := range l.schemas {
+		for _, rule := range Rules {
+			if !opts.Disabled[rule.Name] {
+				l.rule = rule
+				rule.check(l, s)
+			}
+		}
+	}
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		pi, pj := l.problems[i].Pos, l.problems[j].Pos
+		if pi.IsValid() != pj.IsValid() {
+			return pi.IsValid()
+		}
+		return pi.Offset < pj.Offset
+	})
+	return l.problems, nil
+}
+
+// LookupRule returns the rule in Rules with the name, or nil if there is none.
+func LookupRule(name string) *Rule {
+	for _, rule := range Rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// schema is a (sub)schema in the document being linted.
+type schema struct {
+	*jsonschema.Schema
+	path []jsonschema.ReferenceToken // the location of the schema relative to the root
+}
+
+// linter holds the state of a call to Lint.
+type linter struct {
+	registry *jsonschema.Registry
+	schemas  []schema                      // all (sub)schemas in the document, in walk order
+	pointers map[*jsonschema.Schema]string // the JSON Pointer of each (sub)schema
+
+	refTargets map[*jsonschema.Schema]*jsonschema.Schema // $ref-bearing schema -> referenced schema
+	refErrs    map[*jsonschema.Schema]error              // $ref-bearing schema -> resolution error
+
+	rule     *Rule // the rule being run
+	problems []Problem
+}
+
+// report records a problem with the value of the keyword of the schema (or with the schema itself,
+// if keyword is "").
+func (l *linter) report(s schema, keyword string, format string, args ...interface{}) {
+	pos, ok := s.KeywordPos[keyword]
+	if !ok {
+		pos = s.Pos
+	}
+	l.problems = append(l.problems, Problem{
+		Rule:     l.rule.Name,
+		Location: pointer(s.path),
+		Pos:      pos,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// resolveRefs resolves the "$ref" of each schema in the document. References to the JSON Schema
+// meta-schemas (at json-schema.org) are not resolved.
+func (l *linter) resolveRefs() {
+	l.refTargets = map[*jsonschema.Schema]*jsonschema.Schema{}
+	l.refErrs = map[*jsonschema.Schema]error{}
+	for _, s := range l.schemas {
+		if s.Reference == nil {
+			continue
+		}
+		id, _ := l.registry.ID(s.Schema)
+		if ref, err := url.Parse(*s.Reference); err == nil && id.Base != nil && id.Base.ResolveReference(ref).Host == "json-schema.org" {
+			continue
+		}
+		target, err := l.registry.Resolve(id, *s.Reference)
+		if err != nil {
+			l.refErrs[s.Schema] = err
+			continue
+		}
+		l.refTargets[s.Schema] = target
+	}
+}
+
+// isReferenced reports whether a "$ref" in the document refers to the schema (or to one of its
+// subschemas).
+func (l *linter) isReferenced(s *jsonschema.Schema) bool {
+	p, ok := l.pointers[s]
+	if !ok {
+		return false
+	}
+	for _, target := range l.refTargets {
+		if tp, ok := l.pointers[target]; ok && (tp == p || strings.HasPrefix(tp, p+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// keywords returns the keywords (and other properties) of the schema in its raw JSON (or, if it
+// has none, in its KeywordPos), sorted by name.
+func keywords(s *jsonschema.Schema) []string {
+	var names []string
+	var raw map[string]json.RawMessage
+	if s.Raw != nil && json.Unmarshal(*s.Raw, &raw) == nil {
+		for name := range raw {
+			names = append(names, name)
+		}
+	} else {
+		for name := range s.KeywordPos {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func pointer(path []jsonschema.ReferenceToken) string {
+	return jsonschema.FormatJSONPointer(jsonschema.ReferenceTokensPointer(path))
+}
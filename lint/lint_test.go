@@ -0,0 +1,114 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+func TestLint(t *testing.T) {
+	data := `{
+  "title": "root",
+  "type": "object",
+  "required": ["a", "missing"],
+  "properties": {
+    "a": {"$ref": "#/definitions/used", "description": "x"},
+    "b": {"type": "string", "minLength": 5, "maxLength": 3, "pattern": "[a-z]+"},
+    "c": {"$ref": "#/definitions/nonexistent"}
+  },
+  "allOf": [{"type": "object", "properties": {"e": {}}}],
+  "definitions": {
+    "used": {"type": "string", "pattern": "^[a-z]+$"},
+    "unused": {"type": "string"}
+  }
+}`
+	schema, err := jsonschema.ParseSchema([]byte(data), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := Lint(schema, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, p := range problems {
+		got[p.Rule] = p.Location + " " + p.Pos.String()
+	}
+	want := map[string]string{
+		"required-property":  " 4:15",
+		"unused-definition":  "/definitions/unused 13:15",
+		"unresolvable-ref":   "/properties/c 8:19",
+		"ref-siblings":       "/properties/a 6:19",
+		"min-max":            "/properties/b 7:42",
+		"unanchored-pattern": "/properties/b 7:72",
+		"missing-title":      "/allOf/0 10:13",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+		for _, p := range problems {
+			t.Log(p)
+		}
+	}
+
+	for i := 1; i < len(problems); i++ {
+		if problems[i].Pos.Offset < problems[i-1].Pos.Offset {
+			t.Errorf("got problem %s after %s, want them sorted by position", problems[i], problems[i-1])
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		problems, err := Lint(schema, Options{Disabled: map[string]bool{"min-max": true, "unresolvable-ref": true}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range problems {
+			if p.Rule == "min-max" || p.Rule == "unresolvable-ref" {
+				t.Errorf("got problem from disabled rule: %s", p)
+			}
+		}
+		if len(problems) != len(want)-2 {
+			t.Errorf("got %d problems, want %d", len(problems), len(want)-2)
+		}
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		if _, err := Lint(schema, Options{Disabled: map[string]bool{"x": true}}); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+}
+
+func TestLint_refSiblingsDraft(t *testing.T) {
+	data := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "root",
+  "$defs": {
+    "a": {"title": "a", "$ref": "#/$defs/c", "description": "x"},
+    "b": {
+      "$schema": "http://json-schema.org/draft-07/schema#",
+      "$id": "http://example.com/b.json",
+      "title": "b",
+      "definitions": {"c": {"title": "c", "$ref": "#", "description": "x"}}
+    },
+    "c": {"title": "c"}
+  },
+  "$ref": "#/$defs/a"
+}`
+	schema, err := jsonschema.ParseSchema([]byte(data), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := Lint(schema, Options{Disabled: map[string]bool{"unused-definition": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Rule+" "+p.Location)
+	}
+	if want := []string{"ref-siblings /$defs/b/definitions/c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/go-jsonschema/jsonschema"
+)
+
+// Rules are all of the lint rules, in the order in which they are run on each schema.
+var Rules = []*Rule{
+	{
+		Name:  "required-property",
+		Doc:   `a "required" property name is not defined in "properties"`,
+		check: checkRequiredProperty,
+	},
+	{
+		Name:  "unused-definition",
+		Doc:   `a schema in "definitions" or "$defs" is not referred to by any "$ref" in the document`,
+		check: checkUnusedDefinition,
+	},
+	{
+		Name:  "unresolvable-ref",
+		Doc:   `a "$ref" does not refer to any schema`,
+		check: checkUnresolvableRef,
+	},
+	{
+		Name:  "ref-siblings",
+		Doc:   `a schema has keywords alongside "$ref", which are ignored in draft-07 and earlier`,
+		check: checkRefSiblings,
+	},
+	{
+		Name:  "min-max",
+		Doc:   `a minimum (such as "minLength") is greater than the corresponding maximum, so no value is valid`,
+		check: checkMinMax,
+	},
+	{
+		Name:  "unanchored-pattern",
+		Doc:   `a "pattern" is not anchored with ^ and $, so it matches any string that contains a match`,
+		check: checkUnanchoredPattern,
+	},
+	{
+		Name:  "missing-title",
+		Doc:   `an object schema has no "title" and no name from its location, so the compiler can't name its Go type`,
+		check: checkMissingTitle,
+	},
+}
+
+func checkRequiredProperty(l *linter, s schema) {
+	if s.Properties == nil || s.PatternProperties != nil {
+		return // the required properties might be matched by patternProperties
+	}
+	for _, name := range s.Required {
+		if _, ok := (*s.Properties)[name]; !ok {
+			l.report(s, "required", "required property %q is not defined in properties", name)
+		}
+	}
+}
+
+func checkUnusedDefinition(l *linter, s schema) {
+	for _, keyword := range []string{"definitions", "$defs"} {
+		var defs *map[string]*jsonschema.Schema
+		if keyword == "definitions" {
+			defs = s.Definitions
+		} else {
+			defs = s.Defs
+		}
+		if defs == nil {
+			continue
+		}
+		for _, name := range s.OrderedKeys(keyword) {
+			if def := (*defs)[name]; def != nil && !l.isReferenced(def) {
+				path := append(s.path[:len(s.path):len(s.path)], jsonschema.ReferenceToken{Name: keyword, Keyword: true}, jsonschema.ReferenceToken{Name: name})
+				l.report(schema{Schema: def, path: path}, "", "%s %q is not referred to by any $ref", keyword, name)
+			}
+		}
+	}
+}
+
+func checkUnresolvableRef(l *linter, s schema) {
+	if err := l.refErrs[s.Schema]; err != nil {
+		l.report(s, "$ref", "%s", err)
+	}
+}
+
+// refSiblingsAllowed are the keywords that may appear alongside "$ref" without being reported.
+var refSiblingsAllowed = map[string]bool{
+	"$ref":     true,
+	"$comment": true,
+	"!go":      true, // used by the compiler
+}
+
+func checkRefSiblings(l *linter, s schema) {
+	if s.Reference == nil {
+		return
+	}
+	if draft, ok := l.registry.Draft(s.Schema); ok && draft >= jsonschema.Draft201909 {
+		return
+	}
+	var siblings []string
+	for _, keyword := range keywords(s.Schema) {
+		if !refSiblingsAllowed[keyword] {
+			siblings = append(siblings, keyword)
+		}
+	}
+	if len(siblings) > 0 {
+		l.report(s, "$ref", "keywords alongside $ref are ignored: %s", strings.Join(siblings, ", "))
+	}
+}
+
+func checkMinMax(l *linter, s schema) {
+	toFloat := func(v *int64) *float64 {
+		if v == nil {
+			return nil
+		}
+		f := float64(*v)
+		return &f
+	}
+	for _, c := range []struct {
+		min, max       string
+		minVal, maxVal *float64
+		exclusive      bool // whether equal values are contradictory
+	}{
+		{min: "minimum", max: "maximum", minVal: s.Minimum, maxVal: s.Maximum},
+		{min: "exclusiveMinimum", max: "exclusiveMaximum", minVal: s.ExclusiveMinimum, maxVal: s.ExclusiveMaximum, exclusive: true},
+		{min: "minimum", max: "exclusiveMaximum", minVal: s.Minimum, maxVal: s.ExclusiveMaximum, exclusive: true},
+		{min: "exclusiveMinimum", max: "maximum", minVal: s.ExclusiveMinimum, maxVal: s.Maximum, exclusive: true},
+		{min: "minLength", max: "maxLength", minVal: toFloat(s.MinLength), maxVal: toFloat(s.MaxLength)},
+		{min: "minItems", max: "maxItems", minVal: toFloat(s.MinItems), maxVal: toFloat(s.MaxItems)},
+		{min: "minProperties", max: "maxProperties", minVal: toFloat(s.MinProperties), maxVal: toFloat(s.MaxProperties)},
+		{min: "minContains", max: "maxContains", minVal: toFloat(s.MinContains), maxVal: toFloat(s.MaxContains)},
+	} {
+		if c.minVal == nil || c.maxVal == nil {
+			continue
+		}
+		if *c.minVal > *c.maxVal || (c.exclusive && *c.minVal == *c.maxVal) {
+			l.report(s, c.min, "%s (%v) contradicts %s (%v), so no value is valid", c.min, *c.minVal, c.max, *c.maxVal)
+		}
+	}
+}
+
+func checkUnanchoredPattern(l *linter, s schema) {
+	if s.Pattern == nil {
+		return
+	}
+	if p := *s.Pattern; !strings.HasPrefix(p, "^") || !strings.HasSuffix(p, "$") {
+		l.report(s, "pattern", "pattern %q is not anchored with ^ and $, so it matches any string that contains a match", p)
+	}
+}
+
+func checkMissingTitle(l *linter, s schema) {
+	// The compiler emits a named Go type for each object schema with properties (see
+	// compiler.goNameForSchema), except under "if", "then" and "else".
+	if len(s.Type) != 1 || s.Type[0] != jsonschema.ObjectType || s.Properties == nil || (s.Title != nil && *s.Title != "") {
+		return
+	}
+	for _, t := range s.path {
+		if t.Keyword && (t.Name == "if" || t.Name == "then" || t.Name == "else") {
+			return
+		}
+	}
+	for _, t := range s.path {
+		if t.Name != "" && !t.Keyword {
+			return // named by the property or definition name
+		}
+	}
+	l.report(s, "", "object schema has no title and no name from its location, so the compiler can't name its Go type")
+}